@@ -0,0 +1,115 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+const (
+	MIME_TEXT = "text/plain"
+	MIME_PNG  = "image/png"
+
+	// the most encoded bytes terminals commonly take in one osc 52 sequence
+	OSC52_LIMIT = 100000
+)
+
+var ErrNoClipboard = errors.New("no clipboard found, install wl-copy or xclip")
+
+// a program that sets the clipboard, used when it is installed and the
+// display it talks to is set
+type tool struct {
+	name    string
+	display string
+	args    func(mime string) []string
+}
+
+var tools = []tool{
+	{
+		name:    "wl-copy",
+		display: "WAYLAND_DISPLAY",
+		args: func(mime string) []string {
+			return []string{"--type", mime}
+		},
+	},
+	{
+		name:    "xclip",
+		display: "DISPLAY",
+		args: func(mime string) []string {
+			return []string{"-selection", "clipboard", "-t", mime}
+		},
+	},
+}
+
+// the escape sequence that sets the clipboard of the terminal, which
+// reaches the local clipboard over ssh too, tmux only passes it on when
+// it is wrapped
+func OSC52(data []byte, getenv func(string) string) []byte {
+	sequence := "\x1b]52;c;" + base64.StdEncoding.EncodeToString(data) + "\a"
+	if getenv("TMUX") != "" {
+		sequence = "\x1bPtmux;" + strings.ReplaceAll(sequence, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	return []byte(sequence)
+}
+
+// the first tool that is installed and has a display to talk to
+func findTool(getenv func(string) string, lookPath func(string) (string, error)) (tool, string, bool) {
+	for _, t := range tools {
+		if getenv(t.display) == "" {
+			continue
+		}
+		path, err := lookPath(t.name)
+		if err == nil {
+			return t, path, true
+		}
+	}
+	return tool{}, "", false
+}
+
+func remote(getenv func(string) string) bool {
+	return getenv("SSH_TTY") != "" || getenv("SSH_CONNECTION") != ""
+}
+
+// whether text goes through the terminal, which is the only way to reach
+// the local clipboard over ssh, a local tool is preferred otherwise since
+// terminals like gnome terminal ignore osc 52 without saying so
+func viaTerminal(mime string, size int, remote, hasTool bool) bool {
+	if mime != MIME_TEXT || base64.StdEncoding.EncodedLen(size) > OSC52_LIMIT {
+		return false
+	}
+	return remote || !hasTool
+}
+
+// sets the clipboard with wl-copy or xclip, or through the terminal over
+// ssh and when neither is installed, images and long text need a tool
+func Copy(data []byte, mime string) error {
+	t, path, hasTool := findTool(os.Getenv, exec.LookPath)
+	if viaTerminal(mime, len(data), remote(os.Getenv), hasTool) {
+		tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+		if err == nil {
+			defer tty.Close()
+			_, err = tty.Write(OSC52(data, os.Getenv))
+			if err == nil {
+				return nil
+			}
+		}
+	}
+	if !hasTool {
+		return ErrNoClipboard
+	}
+
+	// xclip leaves a child behind that serves the selection until another
+	// program takes it, it would hold pipes for the output open that long,
+	// so the output is left to go to the null device
+	cmd := exec.Command(path, t.args(mime)...)
+	cmd.Stdin = bytes.NewReader(data)
+	err := cmd.Run()
+	if err != nil {
+		return fmt.Errorf("%s: %w", t.name, err)
+	}
+	return nil
+}
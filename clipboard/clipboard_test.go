@@ -0,0 +1,108 @@
+package clipboard
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestOSC52(t *testing.T) {
+	env := map[string]string{}
+	getenv := func(key string) string {
+		return env[key]
+	}
+
+	want := "\x1b]52;c;aWNvbg==\a"
+	if got := string(OSC52([]byte("icon"), getenv)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	env["TMUX"] = "/tmp/tmux"
+	want = "\x1bPtmux;\x1b\x1b]52;c;aWNvbg==\a\x1b\\"
+	if got := string(OSC52([]byte("icon"), getenv)); got != want {
+		t.Errorf("expected the sequence to be wrapped for tmux, got %q", got)
+	}
+}
+
+func TestClipboardRoute(t *testing.T) {
+	env := map[string]string{"DISPLAY": ":0"}
+	getenv := func(key string) string {
+		return env[key]
+	}
+	installed := map[string]bool{"xclip": true}
+	lookPath := func(name string) (string, error) {
+		if installed[name] {
+			return "/usr/bin/" + name, nil
+		}
+		return "", os.ErrNotExist
+	}
+
+	found, path, has := findTool(getenv, lookPath)
+	if !has || found.name != "xclip" || path != "/usr/bin/xclip" {
+		t.Errorf("expected xclip for an x display, got %q", path)
+	}
+	// wl-copy is installed but there is no wayland display to talk to
+	installed["wl-copy"] = true
+	if found, _, _ := findTool(getenv, lookPath); found.name != "xclip" {
+		t.Errorf("expected wl-copy to be skipped without a wayland display, got %s", found.name)
+	}
+
+	cases := []struct {
+		mime     string
+		size     int
+		remote   bool
+		hasTool  bool
+		terminal bool
+	}{
+		// a local tool is more dependable than the terminal
+		{MIME_TEXT, 10, false, true, false},
+		{MIME_TEXT, 10, false, false, true},
+		// the local clipboard is only reachable through the terminal over ssh
+		{MIME_TEXT, 10, true, true, true},
+		{MIME_TEXT, OSC52_LIMIT, true, true, false},
+		{MIME_PNG, 10, true, true, false},
+	}
+	for _, c := range cases {
+		if got := viaTerminal(c.mime, c.size, c.remote, c.hasTool); got != c.terminal {
+			t.Errorf("%+v: expected the terminal to be used %t", c, c.terminal)
+		}
+	}
+
+	if remote(getenv) {
+		t.Error("expected a local session")
+	}
+	env["SSH_TTY"] = "/dev/pts/1"
+	if !remote(getenv) {
+		t.Error("expected an ssh session")
+	}
+}
+
+func TestCopyForkingTool(t *testing.T) {
+	dir := t.TempDir()
+	copied := filepath.Join(dir, "copied")
+	// like xclip, a child stays behind to serve the selection
+	script := "#!/bin/sh\ncat > " + copied + "\nsleep 5 &\n"
+	err := os.WriteFile(filepath.Join(dir, "xclip"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("DISPLAY", ":0")
+	t.Setenv("WAYLAND_DISPLAY", "")
+	t.Setenv("SSH_TTY", "")
+	t.Setenv("SSH_CONNECTION", "")
+
+	start := time.Now()
+	err = Copy([]byte("icon"), MIME_TEXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected copying not to wait for the child, took %s", elapsed)
+	}
+	data, err := os.ReadFile(copied)
+	if err != nil || string(data) != "icon" {
+		t.Errorf("expected the tool to be given the data, got %q %v", data, err)
+	}
+}
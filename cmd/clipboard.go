@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"icon-cli/clipboard"
+	"icon-cli/library"
+	"icon-cli/svelte"
+	"image/png"
+	"strings"
+)
+
+// the size in pixels icons are copied as images in
+const CLIPBOARD_PNG_SIZE = 256
+
+// turns the svg of an icon into what is placed on the clipboard, along
+// with its mime type
+type ClipboardContent = func(name library.TextCase, data []byte) ([]byte, string, error)
+
+var clipboardContents = map[string]ClipboardContent{
+	"svg":       copySVG,
+	"component": copyComponent,
+	"name":      copyName,
+	"png":       copyPNG,
+}
+
+var clipboardKinds = []string{"svg", "component", "name", "png"}
+
+func copySVG(name library.TextCase, data []byte) ([]byte, string, error) {
+	return data, clipboard.MIME_TEXT, nil
+}
+
+func copyComponent(name library.TextCase, data []byte) ([]byte, string, error) {
+	component, err := svelte.Component(data)
+	return []byte(component), clipboard.MIME_TEXT, err
+}
+
+func copyName(name library.TextCase, data []byte) ([]byte, string, error) {
+	return []byte(kebabName(name)), clipboard.MIME_TEXT, nil
+}
+
+func copyPNG(name library.TextCase, data []byte) ([]byte, string, error) {
+	img, err := library.Rasterize(data, CLIPBOARD_PNG_SIZE)
+	if err != nil {
+		return nil, "", err
+	}
+	buffer := bytes.NewBuffer(nil)
+	err = png.Encode(buffer, img)
+	return buffer.Bytes(), clipboard.MIME_PNG, err
+}
+
+// places icons on the clipboard, text of several icons is copied one per
+// line, an image only holds a single icon
+func CopyIcons(version string, refs []library.Ref, kind string) error {
+	content, has := clipboardContents[kind]
+	if !has {
+		return fmt.Errorf("unsupported clipboard content %s", kind)
+	}
+	if kind == "png" && len(refs) > 1 {
+		return fmt.Errorf("only a single icon can be copied as an image, got %d", len(refs))
+	}
+
+	var copies []string
+	var exported []library.Ref
+	mime := clipboard.MIME_TEXT
+	for _, ref := range refs {
+		resolved, data, err := exportData(version, ref)
+		if err != nil {
+			return err
+		}
+		copied, m, err := content(resolved.Name, data)
+		if err != nil {
+			return err
+		}
+		copies = append(copies, string(copied))
+		exported = append(exported, resolved)
+		mime = m
+	}
+	err := clipboard.Copy([]byte(strings.Join(copies, "\n")), mime)
+	if err != nil {
+		return err
+	}
+
+	history.Data.Use(exported...)
+	return history.Write()
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// the config fields that can be read and written from the command line,
+// everything except offline belongs to the library selected with --namespace
+var configFields = map[string]struct {
+	get func(Config, LibraryConfig) string
+	set func(*Config, *LibraryConfig, string) error
+}{
+	"source": {
+		get: func(_ Config, l LibraryConfig) string { return l.Source },
+		set: func(_ *Config, l *LibraryConfig, v string) error {
+			_, err := NewProvider(LibraryConfig{Source: v})
+			if err != nil {
+				return err
+			}
+			err = checkPin(v, l.Version)
+			if err != nil {
+				return err
+			}
+			l.Source = v
+			return nil
+		},
+	},
+	"location": {
+		get: func(_ Config, l LibraryConfig) string { return l.Location },
+		set: func(_ *Config, l *LibraryConfig, v string) error {
+			l.Location = v
+			return nil
+		},
+	},
+	"registry": {
+		get: func(_ Config, l LibraryConfig) string { return l.Registry },
+		set: func(_ *Config, l *LibraryConfig, v string) error {
+			l.Registry = v
+			return nil
+		},
+	},
+	"directory": {
+		get: func(_ Config, l LibraryConfig) string { return l.Directory },
+		set: func(_ *Config, l *LibraryConfig, v string) error {
+			l.Directory = v
+			return nil
+		},
+	},
+	"version": {
+		get: func(_ Config, l LibraryConfig) string { return l.Version },
+		set: func(_ *Config, l *LibraryConfig, v string) error {
+			err := checkPin(l.Source, v)
+			if err != nil {
+				return err
+			}
+			l.Version = v
+			return nil
+		},
+	},
+	"offline": {
+		get: func(c Config, _ LibraryConfig) string { return strconv.FormatBool(c.Offline) },
+		set: func(c *Config, _ *LibraryConfig, v string) error {
+			parsed, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			c.Offline = parsed
+			return nil
+		},
+	},
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	rootCmd.AddCommand(configCmd)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "read and change the configuration",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get [key]",
+	Short: "print a config value, or all of them",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if len(args) == 0 {
+			for _, key := range []string{
+				"source", "location", "registry", "directory", "version", "offline",
+			} {
+				fmt.Printf("%s = %s\n", key, configFields[key].get(cfg.Data, *selected))
+			}
+			return
+		}
+		field, has := configFields[args[0]]
+		if !has {
+			log.Fatalf("unknown config key %s", args[0])
+		}
+		fmt.Println(field.get(cfg.Data, *selected))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "change a config value",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		field, has := configFields[args[0]]
+		if !has {
+			log.Fatalf("unknown config key %s", args[0])
+		}
+		err = field.set(&cfg.Data, selected, args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = cfg.Write()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"icon-cli/library"
+	"icon-cli/svelte"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+type Format = func(name library.TextCase, data []byte, output string) error
+
+var formats = map[string]Format{
+	"svg":    exportSVG,
+	"svelte": exportSvelte,
+}
+
+// formats that write all exported icons of a library into a single file,
+// along with the category of each icon
+type CollectionFormat = func(
+	namespace string, icons map[library.TextCase][]byte,
+	categories map[library.TextCase]string, output string,
+) error
+
+var collectionFormats = map[string]CollectionFormat{
+	"iconify": exportIconify,
+}
+
+func exportSVG(name library.TextCase, data []byte, output string) error {
+	return os.WriteFile(
+		filepath.Join(output, kebabName(name)+".svg"),
+		data, 0666,
+	)
+}
+
+func exportSvelte(name library.TextCase, data []byte, output string) error {
+	component, err := svelte.Component(data)
+	if err != nil {
+		return err
+	}
+	err = os.WriteFile(
+		filepath.Join(output, "icon-context.ts"),
+		[]byte(svelte.IconContext), 0666,
+	)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(
+		filepath.Join(output, componentName(name)+".svelte"),
+		[]byte(component), 0666,
+	)
+}
+
+func exportIconify(
+	namespace string, icons map[library.TextCase][]byte,
+	categories map[library.TextCase]string, output string,
+) error {
+	collection, err := library.NewIconifyCollection(namespace, icons, categories)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(output, namespace+".json"), data, 0666)
+}
+
+// the icon a reference points to in an installed version, version is
+// empty for the version its library uses
+func exportData(version string, ref library.Ref) (library.Ref, []byte, error) {
+	resolved, has := resolveRef(ref, version)
+	if !has {
+		return ref, nil, fmt.Errorf("there is no icon %s", ref)
+	}
+	store := libraries[resolved.Namespace]
+	if version == "" {
+		version = store.Data.Version
+	}
+	if !store.Data.Installed(version) {
+		return resolved, nil, fmt.Errorf("version %s of %s is not installed", version, resolved.Namespace)
+	}
+	data, has := store.Data.Icon(version, resolved.Name)
+	if !has {
+		return resolved, nil, fmt.Errorf("there is no icon %s in %s", resolved, version)
+	}
+	return resolved, data, nil
+}
+
+// writes the given icons into the output directory, version selects
+// an installed version and is empty for the version each library uses
+func Export(version string, refs []library.Ref, format, output string) error {
+	exporter, has := formats[format]
+	collectionExporter, isCollection := collectionFormats[format]
+	if !has && !isCollection {
+		return fmt.Errorf("unsupported output format %s", format)
+	}
+	collections := map[string]map[library.TextCase][]byte{}
+	categories := map[string]map[library.TextCase]string{}
+	var exported []library.Ref
+
+	err := os.MkdirAll(output, 0777)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		resolved, data, err := exportData(version, ref)
+		if err != nil {
+			return err
+		}
+		exported = append(exported, resolved)
+		if isCollection {
+			if collections[resolved.Namespace] == nil {
+				collections[resolved.Namespace] = map[library.TextCase][]byte{}
+				categories[resolved.Namespace] = map[library.TextCase]string{}
+			}
+			collections[resolved.Namespace][resolved.Name] = data
+			store := libraries[resolved.Namespace]
+			installed := version
+			if installed == "" {
+				installed = store.Data.Version
+			}
+			record, _ := store.Data.Record(installed, resolved.Name)
+			categories[resolved.Namespace][resolved.Name] = record.Category
+			continue
+		}
+		err = exporter(resolved.Name, data, output)
+		if err != nil {
+			return err
+		}
+	}
+
+	for namespace, icons := range collections {
+		err := collectionExporter(namespace, icons, categories[namespace], output)
+		if err != nil {
+			return err
+		}
+	}
+
+	// exported icons are listed as recent and rank higher in searches
+	history.Data.Use(exported...)
+	return history.Write()
+}
+
+var exportFormat *string
+var exportOutput *string
+var exportVersion *string
+var exportAll *bool
+var exportClipboard *string
+
+func init() {
+	exportFormat = exportCmd.Flags().StringP(
+		"format", "f", "svg", "the output format, supported formats: [svg, svelte, iconify]",
+	)
+	exportOutput = exportCmd.Flags().StringP(
+		"output", "o", ".", "the directory to store the exported icons",
+	)
+	exportVersion = exportCmd.Flags().String(
+		"version", "", "the installed version to export from (defaults to the version in use)",
+	)
+	exportAll = exportCmd.Flags().Bool(
+		"all", false, "export every icon of the library selected with --namespace",
+	)
+	exportClipboard = exportCmd.Flags().String(
+		"clipboard", "", "copy the icons instead of writing files, supported contents: ["+strings.Join(clipboardKinds, ", ")+"]",
+	)
+	rootCmd.AddCommand(exportCmd)
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [icons...]",
+	Short: "export icons into a project",
+	Long:  "export icons into a project, icons are referenced as namespace:name or just name",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Update(false)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		refs := make([]library.Ref, len(args))
+		for i, a := range args {
+			refs[i] = library.ParseRef(a)
+		}
+		if *exportAll {
+			version := *exportVersion
+			if version == "" {
+				version = iconLibrary.Data.Version
+			}
+			for name := range iconLibrary.Data.Manifests[version] {
+				refs = append(refs, library.Ref{Namespace: selected.Namespace, Name: name})
+			}
+		}
+		if len(refs) == 0 {
+			log.Fatal("no icons to export, name some or pass --all")
+		}
+
+		if *exportClipboard != "" {
+			err = CopyIcons(*exportVersion, refs, *exportClipboard)
+		} else {
+			err = Export(*exportVersion, refs, *exportFormat, *exportOutput)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
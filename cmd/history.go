@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"icon-cli/common"
+	"icon-cli/library"
+	"icon-cli/search"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var history *common.Store[search.History]
+
+// the history is stored next to the --config path
+func historyPath() string {
+	ext := filepath.Ext(*configPath)
+	return strings.TrimSuffix(*configPath, ext) + ".history" + ext
+}
+
+// ranks entries with how often their icons were exported
+func rank(query string, entries []search.Entry) []search.Result {
+	return search.Scorer{Usage: history.Data.Usage}.Rank(search.Parse(query), entries)
+}
+
+func init() {
+	favoritesCmd.AddCommand(favoritesAddCmd)
+	favoritesCmd.AddCommand(favoritesRemoveCmd)
+	rootCmd.AddCommand(favoritesCmd)
+	rootCmd.AddCommand(recentCmd)
+}
+
+var favoritesCmd = &cobra.Command{
+	Use:   "favorites",
+	Short: "list the starred icons",
+	Long:  "list the starred icons, they are pinned to the top of the browser",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, f := range history.Data.Favorites {
+			fmt.Println(f)
+		}
+	},
+}
+
+// stars or unstars icons that aren't already
+func setFavorites(args []string, starred bool) {
+	err := Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, a := range args {
+		ref, has := resolveRef(library.ParseRef(a), "")
+		if !has {
+			log.Fatalf("there is no icon %s", a)
+		}
+		if history.Data.IsFavorite(ref) != starred {
+			history.Data.ToggleFavorite(ref)
+		}
+	}
+	err = history.Write()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+var favoritesAddCmd = &cobra.Command{
+	Use:   "add <icons...>",
+	Short: "star icons",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setFavorites(args, true)
+	},
+}
+
+var favoritesRemoveCmd = &cobra.Command{
+	Use:   "remove <icons...>",
+	Short: "unstar icons",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		setFavorites(args, false)
+	},
+}
+
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "list the recently exported icons, the last one first",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, r := range history.Data.Recent {
+			fmt.Printf("%s\t%d exports\n", r, history.Data.Usage[r])
+		}
+	},
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+	"icon-cli/common"
+	"icon-cli/library"
+	"icon-cli/search"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var cfg *common.Store[Config]
+var libraries map[string]*common.Store[library.Library]
+
+// the library picked with --namespace, commands that work on a
+// single library use these
+var selected *LibraryConfig
+var iconLibrary *common.Store[library.Library]
+
+func defaultLibrary() LibraryConfig {
+	return LibraryConfig{
+		Namespace: "ri",
+		Source:    SOURCE_GITHUB,
+		Location:  "/Remix-Design/RemixIcon",
+	}
+}
+
+// every library is stored in its own file next to the --library path
+func libraryPath(namespace string) string {
+	ext := filepath.Ext(*libPath)
+	return strings.TrimSuffix(*libPath, ext) + "." + namespace + ext
+}
+
+func Load() error {
+	cfg = common.NewStore(*configPath, Config{
+		Libraries: []LibraryConfig{defaultLibrary()},
+	})
+	err := cfg.Load()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Data.Libraries) == 0 {
+		legacy := defaultLibrary()
+		if cfg.Data.Source != "" {
+			legacy.Source = cfg.Data.Source
+			legacy.Location = cfg.Data.Location
+			legacy.Registry = cfg.Data.Registry
+			legacy.Directory = cfg.Data.Directory
+			legacy.Version = cfg.Data.Version
+		}
+		cfg.Data = Config{
+			Libraries: []LibraryConfig{legacy},
+			Offline:   cfg.Data.Offline,
+		}
+	}
+
+	// older versions stored their only library at the --library path itself
+	first := libraryPath(cfg.Data.Libraries[0].Namespace)
+	if _, err := os.Stat(first); os.IsNotExist(err) {
+		err := os.Rename(*libPath, first)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	libraries = map[string]*common.Store[library.Library]{}
+	for _, config := range cfg.Data.Libraries {
+		store := common.NewStore(libraryPath(config.Namespace), library.Library{})
+		err := store.Load()
+		if err != nil {
+			return err
+		}
+		// written right away so the migration only happens once
+		if store.Data.Migrate() {
+			err = store.Write()
+			if err != nil {
+				return err
+			}
+		}
+		libraries[config.Namespace] = store
+	}
+
+	history = common.NewStore(historyPath(), search.NewHistory())
+	err = history.Load()
+	if err != nil {
+		return err
+	}
+
+	selected = &cfg.Data.Libraries[0]
+	if *namespace != "" {
+		index := findLibrary(*namespace)
+		if index < 0 {
+			return fmt.Errorf("there is no library %s", *namespace)
+		}
+		selected = &cfg.Data.Libraries[index]
+	}
+	iconLibrary = libraries[selected.Namespace]
+	return nil
+}
+
+func findLibrary(namespace string) int {
+	for i, config := range cfg.Data.Libraries {
+		if config.Namespace == namespace {
+			return i
+		}
+	}
+	return -1
+}
+
+// fills in the namespace of a reference without one with the first
+// library containing the icon, version is empty for the version in use
+func resolveRef(ref library.Ref, version string) (library.Ref, bool) {
+	contains := func(store *common.Store[library.Library]) bool {
+		v := version
+		if v == "" {
+			v = store.Data.Version
+		}
+		_, has := store.Data.Manifests[v][ref.Name]
+		return has
+	}
+
+	if ref.Namespace != "" {
+		store, has := libraries[ref.Namespace]
+		return ref, has && contains(store)
+	}
+	for _, config := range cfg.Data.Libraries {
+		if contains(libraries[config.Namespace]) {
+			ref.Namespace = config.Namespace
+			return ref, true
+		}
+	}
+	return ref, false
+}
+
+// the svg data of an icon in the version of its library in use
+func lookupIcon(ref library.Ref) ([]byte, bool) {
+	store, has := libraries[ref.Namespace]
+	if !has {
+		return nil, false
+	}
+	return store.Data.Icon(store.Data.Version, ref.Name)
+}
+
+// every icon of every library along with its metadata
+func allEntries() []search.Entry {
+	var entries []search.Entry
+	for _, config := range cfg.Data.Libraries {
+		store := libraries[config.Namespace]
+		for name := range store.Data.Manifests[store.Data.Version] {
+			record, _ := store.Data.Record(store.Data.Version, name)
+			entries = append(entries, search.Entry{
+				Ref:     library.Ref{Namespace: config.Namespace, Name: name},
+				Record:  record,
+				Version: store.Data.Version,
+			})
+		}
+	}
+	return entries
+}
+
+func init() {
+	librariesCmd.AddCommand(librariesListCmd)
+	librariesCmd.AddCommand(librariesAddCmd)
+	librariesCmd.AddCommand(librariesRemoveCmd)
+	rootCmd.AddCommand(librariesCmd)
+}
+
+var librariesCmd = &cobra.Command{
+	Use:   "libraries",
+	Short: "manage the configured icon libraries",
+}
+
+var librariesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the configured libraries",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, config := range cfg.Data.Libraries {
+			store := libraries[config.Namespace]
+			version := store.Data.Version
+			if version == "" {
+				version = "not installed"
+			}
+			fmt.Printf(
+				"%s\t%s %s\t%s\n",
+				config.Namespace, config.Source, config.Location, version,
+			)
+		}
+	},
+}
+
+var librariesAddCmd = &cobra.Command{
+	Use:   "add <namespace> <source> <location>",
+	Short: "add a library, use `config set` for source specific options",
+	Args:  cobra.ExactArgs(3),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if args[0] == "" || strings.Contains(args[0], ":") {
+			log.Fatalf("invalid namespace %q", args[0])
+		}
+		if findLibrary(args[0]) >= 0 {
+			log.Fatalf("library %s already exists", args[0])
+		}
+		config := LibraryConfig{
+			Namespace: args[0],
+			Source:    args[1],
+			Location:  args[2],
+		}
+		_, err = NewProvider(config)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		cfg.Data.Libraries = append(cfg.Data.Libraries, config)
+		err = cfg.Write()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var librariesRemoveCmd = &cobra.Command{
+	Use:   "remove <namespace>",
+	Short: "remove a library and its installed icons",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		index := findLibrary(args[0])
+		if index < 0 {
+			log.Fatalf("there is no library %s", args[0])
+		}
+		if len(cfg.Data.Libraries) == 1 {
+			log.Fatal("cannot remove the only library")
+		}
+		cfg.Data.Libraries = append(
+			cfg.Data.Libraries[:index],
+			cfg.Data.Libraries[index+1:]...,
+		)
+		err = cfg.Write()
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = os.Remove(libraryPath(args[0]))
+		if err != nil && !os.IsNotExist(err) {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"icon-cli/library"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/iancoleman/strcase"
+	"github.com/spf13/cobra"
+)
+
+var migrateFrom *string
+var migrateTo *string
+var migrateSuggest *bool
+var migrateDryRun *bool
+
+func init() {
+	migrateFrom = migrateCmd.Flags().String(
+		"from", "", "the version the project currently references",
+	)
+	migrateTo = migrateCmd.Flags().String(
+		"to", "", "the version to migrate to (defaults to the installed version)",
+	)
+	migrateSuggest = migrateCmd.Flags().Bool(
+		"suggest", false, "also rewrite removed icons to their closest fuzzy match",
+	)
+	migrateDryRun = migrateCmd.Flags().Bool(
+		"dry-run", false, "report the rewrites without touching any files",
+	)
+	migrateCmd.MarkFlagRequired("from")
+	rootCmd.AddCommand(migrateCmd)
+}
+
+// any run of characters an icon reference could be made of, kebab-case
+// file names and PascalCase component names alike, along with the
+// namespace and the extension around them
+var referencePattern = regexp.MustCompile(`[A-Za-z0-9][A-Za-z0-9:-]*(\.svg|\.svelte)?`)
+
+func kebabName(name library.TextCase) string {
+	return strings.ReplaceAll(name, " ", "-")
+}
+
+func componentName(name library.TextCase) string {
+	return strcase.ToCamel(kebabName(name))
+}
+
+// expands a rename map into every textual form a reference can take,
+// exported files, names with the namespace in front like ri-home, ri:home
+// and RiHome, and bare names when they are long enough to not be words
+func referenceMap(namespace string, renames map[library.TextCase]library.TextCase) map[string]string {
+	result := map[string]string{}
+	for from, to := range renames {
+		result[kebabName(from)+".svg"] = kebabName(to) + ".svg"
+		result[componentName(from)+".svelte"] = componentName(to) + ".svelte"
+		result[namespace+"-"+kebabName(from)] = namespace + "-" + kebabName(to)
+		result[namespace+":"+kebabName(from)] = namespace + ":" + kebabName(to)
+		result[strcase.ToCamel(namespace)+componentName(from)] = strcase.ToCamel(namespace) + componentName(to)
+
+		// a single word like home is likely to be just that
+		if strings.Contains(from, " ") {
+			result[kebabName(from)] = kebabName(to)
+			result[componentName(from)] = componentName(to)
+		}
+	}
+	return result
+}
+
+// rewrites the references in a text, returns how many there were
+func rewrite(text []byte, references map[string]string) ([]byte, int) {
+	count := 0
+	rewritten := referencePattern.ReplaceAllFunc(text, func(b []byte) []byte {
+		replacement, has := references[string(b)]
+		if !has {
+			return b
+		}
+		count++
+		return []byte(replacement)
+	})
+	return rewritten, count
+}
+
+func rewriteReferences(path string, references map[string]string) (int, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	// skip anything that doesn't look like text
+	if bytes.IndexByte(original, 0) >= 0 {
+		return 0, nil
+	}
+
+	rewritten, count := rewrite(original, references)
+	if count == 0 || *migrateDryRun {
+		return count, nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, err
+	}
+	return count, os.WriteFile(path, rewritten, info.Mode())
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate [paths...]",
+	Short: "rewrite icon references after upgrading the icon library",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		to := *migrateTo
+		if to == "" {
+			to = iconLibrary.Data.Version
+		}
+		fromManifest, has := iconLibrary.Data.Manifests[*migrateFrom]
+		if !has {
+			log.Fatalf("version %s has never been installed", *migrateFrom)
+		}
+		toManifest, has := iconLibrary.Data.Manifests[to]
+		if !has {
+			log.Fatalf("version %s has never been installed", to)
+		}
+
+		diff := library.Compare(fromManifest, toManifest)
+		fmt.Printf(
+			"%s -> %s: %d added, %d removed, %d renamed, %d changed\n",
+			*migrateFrom, to,
+			len(diff.Added), len(diff.Removed), len(diff.Renamed), len(diff.Changed),
+		)
+
+		renames := map[library.TextCase]library.TextCase{}
+		for old, renamed := range diff.Renamed {
+			renames[old] = renamed
+		}
+		for _, removed := range diff.Removed {
+			suggestions := library.Suggest(removed, toManifest, 3)
+			if len(suggestions) == 0 {
+				fmt.Printf("  %s was removed, no suggestions\n", kebabName(removed))
+				continue
+			}
+			names := make([]string, len(suggestions))
+			for i, s := range suggestions {
+				names[i] = kebabName(s)
+			}
+			fmt.Printf(
+				"  %s was removed, did you mean %s?\n",
+				kebabName(removed), strings.Join(names, ", "),
+			)
+			if *migrateSuggest {
+				renames[removed] = suggestions[0]
+			}
+		}
+		references := referenceMap(selected.Namespace, renames)
+
+		if len(args) == 0 {
+			args = []string{"."}
+		}
+		for _, root := range args {
+			err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() {
+					if (path != root && strings.HasPrefix(d.Name(), ".")) ||
+						d.Name() == "node_modules" {
+						return filepath.SkipDir
+					}
+					return nil
+				}
+				count, err := rewriteReferences(path, references)
+				if err != nil {
+					return err
+				}
+				if count > 0 {
+					fmt.Printf("%s: %d references\n", path, count)
+				}
+				return nil
+			})
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+	},
+}
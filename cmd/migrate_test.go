@@ -0,0 +1,34 @@
+package cmd
+
+import "testing"
+
+func TestRewrite(t *testing.T) {
+	references := referenceMap("ri", map[string]string{
+		"arrow left line": "arrow left s line",
+		"home":            "house",
+	})
+	cases := []struct {
+		text     string
+		expected string
+	}{
+		// exported files and components
+		{`<img src="icons/arrow-left-line.svg">`, `<img src="icons/arrow-left-s-line.svg">`},
+		{`import ArrowLeftLine from "./ArrowLeftLine.svelte"`, `import ArrowLeftSLine from "./ArrowLeftSLine.svelte"`},
+		{`<i class="ri-arrow-left-line"></i>`, `<i class="ri-arrow-left-s-line"></i>`},
+		{`<RiArrowLeftLine />`, `<RiArrowLeftSLine />`},
+		{`icon: "ri:arrow-left-line"`, `icon: "ri:arrow-left-s-line"`},
+		// single words are only rewritten where they are clearly icons
+		{`import Home from "./Home.svelte"`, `import Home from "./House.svelte"`},
+		{`<i class="ri-home"></i> <img src="home.svg">`, `<i class="ri-house"></i> <img src="house.svg">`},
+		{`go home, the settings are at home`, `go home, the settings are at home`},
+		{`<a href="/home">Home</a>`, `<a href="/home">Home</a>`},
+		// names inside other names and other namespaces stay
+		{`sort-arrow-left-line mdi:arrow-left-line`, `sort-arrow-left-line mdi:arrow-left-line`},
+	}
+	for _, c := range cases {
+		got, _ := rewrite([]byte(c.text), references)
+		if string(got) != c.expected {
+			t.Errorf("%s: expected %s, got %s", c.text, c.expected, got)
+		}
+	}
+}
@@ -1,17 +1,23 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
+	"fmt"
 	"icon-cli/common"
+	"icon-cli/graphics"
 	"icon-cli/library"
+	"icon-cli/search"
 	"icon-cli/widgets"
 	"image"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	_ "image/jpeg"
 
-	"github.com/lithammer/fuzzysearch/fuzzy"
 	"github.com/mum4k/termdash"
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/container"
@@ -23,31 +29,110 @@ import (
 	"github.com/mum4k/termdash/widgets/textinput"
 	"github.com/spf13/cobra"
 	"github.com/spf13/cobra/doc"
-	"github.com/srwiley/oksvg"
-	"github.com/srwiley/rasterx"
 )
 
-var imageRes = 200
+// how icons are drawn in the preview pane, changed with its keys
+var preview = graphics.NewPreview(200)
 
-func renderSVG(id string) image.Image {
-	buffer := bytes.NewBuffer(
-		iconLibrary.Data.Index[id],
-	)
-	icon, err := oksvg.ReadIconStream(buffer)
-	if err != nil {
-		log.Println(err)
-		return nil
+const (
+	// joins the ids of the variants drawn next to each other in the preview
+	VARIANT_SEPARATOR = "|"
+	// the space between the variants in pixels
+	VARIANT_GAP = 16
+)
+
+func renderSVG(id string, p graphics.Preview) (image.Image, error) {
+	var rendered []image.Image
+	for _, variant := range strings.Split(id, VARIANT_SEPARATOR) {
+		data, _ := lookupIcon(library.ParseRef(variant))
+		img, err := p.Render(data)
+		if err != nil {
+			return nil, err
+		}
+		rendered = append(rendered, img)
 	}
-	icon.SetTarget(0, 0, float64(imageRes), float64(imageRes))
-	rgba := image.NewRGBA(image.Rect(0, 0, imageRes, imageRes))
-	dasher := rasterx.NewDasher(
-		imageRes, imageRes,
-		rasterx.NewScannerGV(
-			imageRes, imageRes, rgba, rgba.Bounds(),
-		),
-	)
-	icon.Draw(dasher, 1)
-	return rgba
+	if len(rendered) == 1 {
+		return rendered[0], nil
+	}
+	return graphics.SideBySide(rendered, VARIANT_GAP), nil
+}
+
+// a row of the browser, the icon with the styles it comes in
+func groupRow(g search.Group) string {
+	if len(g.Variants) < 2 {
+		return g.Variants[0].Ref.String()
+	}
+	styles := g.Styles()
+	sort.Strings(styles)
+	return g.Ref.String() + "  " + strings.Join(styles, " ")
+}
+
+const (
+	// mark the rows pinned to the top of the browser
+	FAVORITE_PREFIX = " ★ "
+	RECENT_PREFIX   = " ◷ "
+)
+
+// the namespaces of the entries with their categories under them, and
+// every node by its key so they can be counted again
+func categoryTree(entries []search.Entry) (*widgets.TreeNode, map[string]*widgets.TreeNode) {
+	all := &widgets.TreeNode{Key: "", Label: "all libraries"}
+	nodes := map[string]*widgets.TreeNode{"": all}
+	for _, e := range entries {
+		namespace, has := nodes[e.Ref.Namespace]
+		if !has {
+			namespace = &widgets.TreeNode{Key: e.Ref.Namespace, Label: e.Ref.Namespace}
+			nodes[namespace.Key] = namespace
+			all.Children = append(all.Children, namespace)
+		}
+		key := search.CategoryKey(e)
+		if _, has := nodes[key]; !has {
+			category := &widgets.TreeNode{Key: key, Label: strings.TrimPrefix(key, namespace.Key+"/")}
+			nodes[key] = category
+			namespace.Children = append(namespace.Children, category)
+		}
+	}
+	for _, node := range nodes {
+		sort.Slice(node.Children, func(i, j int) bool {
+			return node.Children[i].Label < node.Children[j].Label
+		})
+	}
+	return all, nodes
+}
+
+// the variant of a group that is exported, the first unless another style
+// was chosen
+func chosenVariant(g search.Group, style string) library.Ref {
+	for i, s := range g.Styles() {
+		if s == style {
+			return g.Variants[i].Ref
+		}
+	}
+	return g.Variants[0].Ref
+}
+
+const (
+	// how many rendered previews are kept around
+	RENDER_CACHE   = 128
+	RENDER_WORKERS = 2
+	// how many icons on each side of the hovered one are rendered ahead
+	PREFETCH = 3
+
+	REDRAW_INTERVAL = 50 * time.Millisecond
+)
+
+// the icons around a row, which are likely to be hovered next
+func neighbours(ids []string, i int) []string {
+	var around []string
+	for d := 1; d <= PREFETCH; d++ {
+		if i+d < len(ids) {
+			around = append(around, ids[i+d])
+		}
+		if i-d >= 0 {
+			around = append(around, ids[i-d])
+		}
+	}
+	return around
 }
 
 const (
@@ -55,26 +140,142 @@ const (
 	FOCUS_LIST
 )
 
+const (
+	// the container holding either the list or the grid of results
+	RESULTS_ID = "results"
+	// the container around the search and its results
+	BROWSER_ID = "browser"
+	// the container around the preview
+	PREVIEW_ID = "preview"
+)
+
+// the border title of the browser, what the results are limited to and
+// how the last action went
+type browserTitle struct {
+	// shown when the update check failed and the installed libraries are used
+	status string
+	// the label of the category tree node the results are limited to
+	category string
+	// the number of selected icons
+	selection string
+	// the outcome of the last action, cleared when the selection changes
+	message string
+}
+
+func (t browserTitle) String() string {
+	title := t.status
+	if t.category != "" {
+		title += fmt.Sprintf(" in %s ", t.category)
+	}
+	return title + t.selection + t.message
+}
+
+func (t *browserTitle) selectionChanged(count int) {
+	t.selection = ""
+	if count > 0 {
+		t.selection = fmt.Sprintf(" %d selected ", count)
+	}
+	t.message = ""
+}
+
+// reports how an action on the picked icons went, a successful one clears
+// the selection first so the report isn't cleared along with it
+func (t *browserTitle) finish(list *widgets.List, err error, failed, done string) {
+	if err != nil {
+		log.Println(err)
+		t.message = failed
+		return
+	}
+	list.ClearSelection()
+	t.message = done
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "icon",
 	Short: "an SVG icon cli",
-	Long:  "a framework/toolchain agnostic method of incorporating SVG icons into projects.",
+	Long: `a framework/toolchain agnostic method of incorporating SVG icons into projects.
+
+keys in the browser, ctrl+a and ctrl+e move the cursor of the search:
+  ctrl+g      switch between the list and the gallery
+  ctrl+r      browse the categories, enter limits the results to one
+  ctrl+s      list the icons that look like the hovered one
+  ctrl+space  select the hovered icon
+  ctrl+v      start or finish selecting a range of icons
+  ctrl+l      select all results
+  ctrl+t      choose whether the line or fill variant is exported
+  ctrl+f      star the hovered icon, starred and recently exported icons
+              are pinned to the top
+  ctrl+w      export the selection, or the hovered icon without one
+  ctrl+y      copy the selection or the hovered icon, see --clipboard
+  ctrl+z      zoom the preview
+  ctrl+b      switch the preview background, except in braille
+  ctrl+o      switch the color icons are drawn in
+  ctrl+p      draw the icon at 16, 20 or 24 pixels with a pixel grid
+  esc         clear the search
+  ctrl+x      quit`,
 	Run: func(cmd *cobra.Command, args []string) {
+		protocol := *graphicsProtocol
+		switch protocol {
+		case graphics.PROTOCOL_AUTO, graphics.PROTOCOL_BRAILLE, graphics.PROTOCOL_HALFBLOCK,
+			graphics.PROTOCOL_KITTY, graphics.PROTOCOL_ITERM, graphics.PROTOCOL_SIXEL:
+		default:
+			log.Fatalf("unsupported graphics protocol %s", protocol)
+		}
+		if _, has := clipboardContents[*browserClipboard]; !has {
+			log.Fatalf("unsupported clipboard content %s", *browserClipboard)
+		}
+
 		err := Update(false)
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		term, err := tcell.New()
+		tcellTerm, err := tcell.New()
 		if err != nil {
 			log.Fatal(err)
 		}
-		defer term.Close()
+		defer tcellTerm.Close()
+		// protocol images are drawn once tcell has written a frame
+		term := widgets.NewFrameTerminal(tcellTerm)
 
 		img := widgets.NewImage()
 		if err != nil {
 			log.Fatal(err)
 		}
+		if protocol == graphics.PROTOCOL_AUTO {
+			protocol = graphics.Detect(os.Getenv)
+		}
+		switch protocol {
+		case graphics.PROTOCOL_HALFBLOCK:
+			img.SetProps(func(ip widgets.ImageProps) widgets.ImageProps {
+				ip.Protocol = protocol
+				return ip
+			})
+		case graphics.PROTOCOL_KITTY, graphics.PROTOCOL_ITERM, graphics.PROTOCOL_SIXEL:
+			tty, err := os.OpenFile("/dev/tty", os.O_WRONLY, 0)
+			// without the tty the preview stays in braille
+			if err != nil {
+				log.Println(err)
+			} else {
+				defer tty.Close()
+				defer graphics.Clear(tty, protocol)
+				img.SetProps(func(ip widgets.ImageProps) widgets.ImageProps {
+					ip.Protocol = protocol
+					ip.Terminal = tty
+					ip.AfterFlush = term.AfterFlush
+					// the preview sits inside the border in the top left corner
+					ip.Origin = image.Pt(1, 1)
+					ip.CellSize = graphics.CellSize(tty)
+					return ip
+				})
+			}
+		}
+		// braille has no colors, the others would hide black icons on a
+		// dark terminal without a background
+		colored := img.Props().Protocol != graphics.PROTOCOL_BRAILLE
+		if colored {
+			preview.Background = graphics.BACKGROUND_LIGHT
+		}
 
 		list := widgets.NewList()
 		list.SetProps(func(lp widgets.ListProps) widgets.ListProps {
@@ -82,75 +283,214 @@ var rootCmd = &cobra.Command{
 			lp.MouseScope = widgetapi.MouseScopeGlobal
 			return lp
 		})
-		list.Prefix = widgets.NumberPrefix
-
-		indexIds := make([]string, len(iconLibrary.Data.Index))
-		i := 0
-		for k := range iconLibrary.Data.Index {
-			indexIds[i] = k
-			i++
+		// the number of starred and recent rows pinned to the top
+		pinnedFavorites, pinnedRecent := 0, 0
+		list.Prefix = func(i int) string {
+			switch {
+			case i < pinnedFavorites:
+				return FAVORITE_PREFIX
+			case i < pinnedFavorites+pinnedRecent:
+				return RECENT_PREFIX
+			}
+			return widgets.NumberPrefix(i - pinnedFavorites - pinnedRecent)
 		}
+		list.SetProps(func(lp widgets.ListProps) widgets.ListProps {
+			lp.Empty = "no results"
+			return lp
+		})
+
+		grid := widgets.NewGrid()
+		grid.SetProps(func(gp widgets.GridProps) widgets.GridProps {
+			gp.KeyboardScope = widgetapi.KeyScopeGlobal
+			gp.Empty = "no results"
+			return gp
+		})
+
+		entries := allEntries()
 
-		var iconIndexIds []string
+		categories, categoryNodes := categoryTree(entries)
+		tree := widgets.NewTree(categories)
+		tree.SetProps(func(tp widgets.TreeProps) widgets.TreeProps {
+			tp.KeyboardScope = widgetapi.KeyScopeGlobal
+			tp.MouseScope = widgetapi.MouseScopeGlobal
+			tp.Empty = "no libraries"
+			return tp
+		})
 
-		updateListItems := func(items []string) {
-			iconIndexIds = items
+		// icons that only differ in their style share a row
+		var groups []search.Group
+		// the variant of each row that is exported, and all of its
+		// variants which the preview draws next to each other
+		var iconIndexIds, previewIds []string
+		// guards the rows above, the grid draws its thumbnails from them
+		// while the results change, it is only taken after the grid's lock
+		var rowsLock sync.Mutex
+		// the group and exported variant of a row, false once the results
+		// changed and the row is gone
+		row := func(i int) (search.Group, string, bool) {
+			defer rowsLock.Unlock()
+			rowsLock.Lock()
+			if i < 0 || i >= len(groups) {
+				return search.Group{}, "", false
+			}
+			return groups[i], iconIndexIds[i], true
+		}
+		// the style chosen for a group, by its reference
+		chosen := map[string]string{}
+		// the group of every row shown, the selection outlives searches
+		rowGroups := map[string]search.Group{}
+
+		updateListItems := func(items []search.Entry, pin bool) {
+			newGroups := search.GroupVariants(items)
+			pinnedFavorites, pinnedRecent = 0, 0
+			if pin {
+				newGroups, pinnedFavorites, pinnedRecent = history.Data.Pin(newGroups)
+			}
+			rows := make([]string, len(newGroups))
+			names := make([]string, len(newGroups))
+			newIconIds := make([]string, len(newGroups))
+			newPreviewIds := make([]string, len(newGroups))
+			for i, g := range newGroups {
+				rows[i] = groupRow(g)
+				rowGroups[rows[i]] = g
+				names[i] = g.Ref.Name
+				newIconIds[i] = chosenVariant(g, chosen[g.Ref.String()]).String()
+				ids := make([]string, len(g.Variants))
+				for j, v := range g.Variants {
+					ids[j] = v.Ref.String()
+				}
+				newPreviewIds[i] = strings.Join(ids, VARIANT_SEPARATOR)
+			}
+			// the rows are swapped under the grid's lock so it never draws
+			// the new tiles with the old icons, and it is reset before the
+			// list so that nothing hovers a tile past the new results
+			grid.SetProps(func(gp widgets.GridProps) widgets.GridProps {
+				rowsLock.Lock()
+				groups, iconIndexIds, previewIds = newGroups, newIconIds, newPreviewIds
+				rowsLock.Unlock()
+				gp.Tiles = names
+				return gp
+			})
 			list.SetProps(func(lp widgets.ListProps) widgets.ListProps {
-				lp.Rows = items
+				lp.Rows = rows
 				return lp
 			})
+			// the list only hovers something when it has rows
+			if len(newGroups) == 0 {
+				img.SetProps(func(ip widgets.ImageProps) widgets.ImageProps {
+					ip.Image = image.NewRGBA(image.Rect(0, 0, preview.Res, preview.Res))
+					ip.Stretched = false
+					return ip
+				})
+			}
 		}
 
-		updateListItems(indexIds)
-
-		img.SetProps(func(ip widgets.ImageProps) widgets.ImageProps {
-			ip.Image = renderSVG(iconIndexIds[0])
-			return ip
+		ctx, cancel := context.WithCancel(context.Background())
+		// braille previews are cached widened, so showing one doesn't
+		// resize it on the ui's goroutine
+		braille := img.Props().Protocol == graphics.PROTOCOL_BRAILLE
+		ratio := img.Props().CharAspectRatio
+		renderer := graphics.NewRenderer(RENDER_CACHE, func(id string, p graphics.Preview) (image.Image, error) {
+			rendered, err := renderSVG(id, p)
+			if err != nil || !braille {
+				return rendered, err
+			}
+			return widgets.Stretch(rendered, ratio), nil
 		})
-
-		list.OnHover = func(i int) {
-			rendered := renderSVG(iconIndexIds[i])
+		renderer.OnRender = func(id string, rendered image.Image) {
 			img.SetProps(func(ip widgets.ImageProps) widgets.ImageProps {
 				ip.Image = rendered
+				ip.Stretched = braille
 				return ip
 			})
 		}
+		renderer.Start(ctx, RENDER_WORKERS)
+
+		// set once the layout exists, the list hovers its first row before
+		var setPreviewTitle func(int)
+		list.OnHover = func(i int) {
+			rowsLock.Lock()
+			if i < 0 || i >= len(previewIds) {
+				rowsLock.Unlock()
+				return
+			}
+			id, around := previewIds[i], neighbours(previewIds, i)
+			rowsLock.Unlock()
+			renderer.Request(id, preview, around)
+			if setPreviewTitle != nil {
+				setPreviewTitle(i)
+			}
+		}
+		grid.OnHover = list.OnHover
+		// called while the grid draws, so its lock is held
+		grid.Thumbnail = func(i int, size int) image.Image {
+			_, id, has := row(i)
+			if !has {
+				return nil
+			}
+			data, _ := lookupIcon(library.ParseRef(id))
+			rgba, err := library.Rasterize(data, size)
+			if err != nil {
+				return nil
+			}
+			return rgba
+		}
+		// the search and the node of the category tree it is limited to
+		query, category := "", ""
+		refresh := func() {
+			results := entries
+			if query != "" {
+				results = search.ResultEntries(rank(query, entries))
+			}
+			// the tree counts the results outside of the picked node too,
+			// the nodes stay the same so it only needs to be drawn again
+			counts := search.CountCategories(search.GroupVariants(results))
+			for key, node := range categoryNodes {
+				node.Count = counts[key]
+			}
+			// a search already ranks the icons used most first
+			updateListItems(search.FilterCategory(results, category), query == "")
+		}
+		refresh()
 
 		input, err := textinput.New(
 			textinput.FillColor(cell.ColorBlack),
 			textinput.PlaceHolder("Search"),
 			textinput.PlaceHolderColor(cell.ColorLime),
 			textinput.OnChange(func(data string) {
-				if data == "" {
-					updateListItems(indexIds)
-					return
-				}
-				ranks := fuzzy.RankFindFold(data, indexIds)
-				if ranks.Len() > 0 {
-					results := make([]string, ranks.Len())
-					for i, r := range ranks {
-						results[i] = r.Target
-					}
-					updateListItems(results)
-				}
+				query = data
+				refresh()
 			}),
 		)
 		if err != nil {
 			log.Fatal(err)
 		}
 
-		root, err := container.New(
+		// shown when the update check failed and the installed library is used
+		title := browserTitle{}
+		if staleReason != nil {
+			title.status = " offline, showing the installed libraries "
+		}
+
+		var root *container.Container
+		root, err = container.New(
 			term,
 			// container.KeyFocusNext(keyboard.KeyTab),
 			container.Border(linestyle.None),
 			container.SplitVertical(
 				container.Left(
+					container.ID(PREVIEW_ID),
 					container.Border(linestyle.Round),
+					container.BorderTitle(preview.String()),
 					container.KeyFocusSkip(),
 					container.PlaceWidget(img),
 				),
 				container.Right(
+					container.ID(BROWSER_ID),
 					container.Border(linestyle.Round),
+					container.BorderTitle(title.String()),
+					container.BorderTitleAlignRight(),
+					container.TitleColor(cell.ColorYellow),
 					container.SplitHorizontal(
 						container.Top(
 							container.PaddingLeft(1),
@@ -159,6 +499,7 @@ var rootCmd = &cobra.Command{
 							container.PlaceWidget(input),
 						),
 						container.Bottom(
+							container.ID(RESULTS_ID),
 							container.PlaceWidget(list),
 						),
 						container.SplitFixed(1),
@@ -170,19 +511,200 @@ var rootCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		ctx, cancel := context.WithCancel(context.Background())
+		// every update makes termdash clear the screen, which also erases
+		// the preview when a graphics protocol drew it
+		update := func(id string, opts ...container.Option) {
+			err := root.Update(id, opts...)
+			if err != nil {
+				log.Println(err)
+			}
+			img.Invalidate()
+		}
+
+		showingGrid, showingTree := false, false
+		placeResults := func() {
+			var results widgetapi.Widget = list
+			switch {
+			case showingTree:
+				results = tree
+			case showingGrid:
+				results = grid
+			}
+			update(RESULTS_ID, container.PlaceWidget(results))
+		}
+		hovered := func() int {
+			if showingGrid {
+				return grid.Props().Hovered
+			}
+			return list.Props().Hovered
+		}
+		// the preview controls, and which variant is exported when the
+		// hovered icon has several
+		setPreviewTitle = func(i int) {
+			title := preview.String()
+			if g, id, has := row(i); has && len(g.Variants) > 1 {
+				title += fmt.Sprintf(" exporting %s ", library.StyleFromName(id))
+			}
+			update(PREVIEW_ID, container.BorderTitle(title))
+		}
+		setPreviewTitle(hovered())
+
+		setTitle := func() {
+			update(BROWSER_ID, container.BorderTitle(title.String()))
+		}
+		// limits the results to the picked node and shows them again
+		tree.OnSelect = func(key string) {
+			category = key
+			title.category = ""
+			if key != "" {
+				title.category = categoryNodes[key].Label
+			}
+			showingTree = false
+			refresh()
+			placeResults()
+			setTitle()
+		}
+		list.OnSelectionChange = func(count int) {
+			title.selectionChanged(count)
+			setTitle()
+		}
+
+		// the selection, or the hovered icon without one
+		picked := func() []library.Ref {
+			var refs []library.Ref
+			for _, row := range list.Selection() {
+				g := rowGroups[row]
+				refs = append(refs, chosenVariant(g, chosen[g.Ref.String()]))
+			}
+			if _, id, has := row(hovered()); len(refs) == 0 && has {
+				refs = []library.Ref{library.ParseRef(id)}
+			}
+			return refs
+		}
+		// draws the hovered icon again after the preview controls changed
+		setPreview := func(p graphics.Preview) {
+			preview = p
+			// braille only keeps the shape of the icon, its dots take the color
+			if braille {
+				img.SetProps(func(ip widgets.ImageProps) widgets.ImageProps {
+					ip.Foreground = p.Color
+					return ip
+				})
+			}
+			setPreviewTitle(hovered())
+			list.OnHover(hovered())
+		}
+
 		handler := termdash.KeyboardSubscriber(func(k *terminalapi.Keyboard) {
 			switch k.Key {
 			case keyboard.KeyCtrlX:
 				cancel()
 			case keyboard.KeyEsc:
 				input.ReadAndClear()
+			// ctrl+e moves the cursor of the search to its end
+			case keyboard.KeyCtrlW:
+				refs := picked()
+				err := Export("", refs, *browserFormat, *browserOutput)
+				title.finish(
+					list, err, " export failed, see latest.log ",
+					fmt.Sprintf(" exported %d icons to %s ", len(refs), *browserOutput),
+				)
+				setTitle()
+			case keyboard.KeyCtrlY:
+				refs := picked()
+				if len(refs) == 0 {
+					break
+				}
+				err := CopyIcons("", refs, *browserClipboard)
+				title.finish(
+					list, err, " copying failed, see latest.log ",
+					fmt.Sprintf(" copied %d icons as %s ", len(refs), *browserClipboard),
+				)
+				setTitle()
+			case keyboard.KeyCtrlF:
+				// stars the hovered variant, it is pinned from the next search on
+				_, id, has := row(hovered())
+				if !has {
+					break
+				}
+				ref := library.ParseRef(id)
+				title.message = fmt.Sprintf(" unstarred %s ", ref)
+				if history.Data.ToggleFavorite(ref) {
+					title.message = fmt.Sprintf(" starred %s ", ref)
+				}
+				err := history.Write()
+				if err != nil {
+					log.Println(err)
+					title.message = " starring failed, see latest.log "
+				}
+				setTitle()
+			case keyboard.KeyCtrlT:
+				// switches the hovered icon to its next variant for exports
+				i := hovered()
+				g, id, has := row(i)
+				if !has {
+					break
+				}
+				styles := g.Styles()
+				current := library.ParseRef(id)
+				for j, v := range g.Variants {
+					if v.Ref == current {
+						chosen[g.Ref.String()] = styles[(j+1)%len(styles)]
+						break
+					}
+				}
+				rowsLock.Lock()
+				if i < len(iconIndexIds) {
+					iconIndexIds[i] = chosenVariant(g, chosen[g.Ref.String()]).String()
+				}
+				rowsLock.Unlock()
+				setPreviewTitle(i)
+			case keyboard.KeyCtrlZ:
+				setPreview(preview.NextZoom())
+			case keyboard.KeyCtrlB:
+				if colored {
+					setPreview(preview.NextBackground())
+				}
+			case keyboard.KeyCtrlO:
+				setPreview(preview.NextColor())
+			case keyboard.KeyCtrlP:
+				setPreview(preview.NextGrid())
+			case keyboard.KeyCtrlG:
+				// switches the results between the list and the gallery
+				showingGrid = !showingGrid
+				showingTree = false
+				if showingGrid {
+					grid.Hover(list.Props().Hovered)
+				} else {
+					list.Hover(grid.Props().Hovered)
+				}
+				placeResults()
+			case keyboard.KeyCtrlR:
+				// shows the category tree in place of the results
+				showingTree = !showingTree
+				placeResults()
+			case keyboard.KeyCtrlS:
+				// lists the icons that look like the hovered one, nearest first
+				_, id, has := row(hovered())
+				if !has {
+					break
+				}
+				ref := library.ParseRef(id)
+				target, has := refFingerprint(ref)
+				if !has {
+					break
+				}
+				updateListItems(search.ResultEntries(similarEntries(target, ref, entries)), false)
 			}
 		})
 		errorHandler := termdash.ErrorHandler(func(err error) {
 			log.Println(err)
 		})
-		err = termdash.Run(ctx, term, root, handler, errorHandler)
+		err = termdash.Run(
+			ctx, term, root, handler, errorHandler,
+			// previews are rendered in the background and show up on the next redraw
+			termdash.RedrawInterval(REDRAW_INTERVAL),
+		)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -192,6 +714,12 @@ var rootCmd = &cobra.Command{
 // var verbose *bool
 var libPath *string
 var configPath *string
+var offline *bool
+var namespace *string
+var graphicsProtocol *string
+var browserFormat *string
+var browserOutput *string
+var browserClipboard *string
 
 func GenerateDocs(dir string) error {
 	return doc.GenMarkdownTree(rootCmd, dir)
@@ -211,4 +739,24 @@ func init() {
 		"config", "c", library.NewPath(common.RootFolder, "config.bin").String(),
 		"specify where the config should be stored",
 	)
+	namespace = rootCmd.PersistentFlags().StringP(
+		"namespace", "n", "",
+		"the library to work on, defaults to the first one configured",
+	)
+	offline = rootCmd.PersistentFlags().Bool(
+		"offline", false, "don't check for updates, only use the installed library",
+	)
+	graphicsProtocol = rootCmd.Flags().String(
+		"graphics", graphics.PROTOCOL_AUTO,
+		"how the preview is drawn, supported protocols: ["+strings.Join(graphics.Protocols, ", ")+"]",
+	)
+	browserFormat = rootCmd.Flags().StringP(
+		"format", "f", "svg", "the format icons are exported in from the browser, supported formats: [svg, svelte, iconify]",
+	)
+	browserOutput = rootCmd.Flags().StringP(
+		"output", "o", ".", "the directory icons are exported to from the browser",
+	)
+	browserClipboard = rootCmd.Flags().String(
+		"clipboard", "svg", "what ctrl+y copies from the browser, supported contents: ["+strings.Join(clipboardKinds, ", ")+"]",
+	)
 }
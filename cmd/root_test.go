@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"errors"
+	"icon-cli/widgets"
+	"testing"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+func TestBrowserTitle(t *testing.T) {
+	title := browserTitle{status: " offline "}
+	list := widgets.NewList("a", "b")
+	list.OnSelectionChange = title.selectionChanged
+
+	list.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyCtrlL}, nil)
+	if got := title.String(); got != " offline  2 selected " {
+		t.Errorf("expected the selection in the title, got %q", got)
+	}
+
+	title.finish(list, errors.New("disk full"), " export failed ", " exported 2 icons ")
+	if got := title.String(); got != " offline  2 selected  export failed " {
+		t.Errorf("expected a failure to keep the selection, got %q", got)
+	}
+
+	title.finish(list, nil, " export failed ", " exported 2 icons ")
+	if got := title.String(); got != " offline  exported 2 icons " {
+		t.Errorf("expected the export to be reported after the selection cleared, got %q", got)
+	}
+	if len(list.Selection()) != 0 {
+		t.Error("expected the selection to be cleared")
+	}
+
+	title.category = "System"
+	list.Keyboard(&terminalapi.Keyboard{Key: keyboard.KeyCtrlSpace}, nil)
+	if got := title.String(); got != " offline  in System  1 selected " {
+		t.Errorf("expected a new selection to replace the report, got %q", got)
+	}
+}
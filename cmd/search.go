@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"icon-cli/search"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+// the name an icon is written as on the command line, like ri:arrow-left-line
+func refName(e search.Entry) string {
+	return e.Ref.Namespace + ":" + kebabName(e.Ref.Name)
+}
+
+type SearchFormat = func(w io.Writer, results []search.Result) error
+
+var searchFormats = map[string]SearchFormat{
+	"table": printTable,
+	"json":  printJSON,
+	"names": printNames,
+}
+
+func printTable(w io.Writer, results []search.Result) error {
+	table := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(table, "#\tICON\tSCORE\tSTYLE\tCATEGORY\tVERSION")
+	for i, r := range results {
+		fmt.Fprintf(
+			table, "%d\t%s\t%.1f\t%s\t%s\t%s\n",
+			i+1, refName(r.Entry), r.Score,
+			r.Record.Style, r.Record.Category, r.Version,
+		)
+	}
+	return table.Flush()
+}
+
+// a search result as it is written by --format json
+type searchResult struct {
+	Ref       string   `json:"ref"`
+	Namespace string   `json:"namespace"`
+	Name      string   `json:"name"`
+	Score     float64  `json:"score"`
+	Distance  int      `json:"distance"`
+	Style     string   `json:"style,omitempty"`
+	Category  string   `json:"category,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Path      string   `json:"path,omitempty"`
+	Version   string   `json:"version"`
+}
+
+func printJSON(w io.Writer, results []search.Result) error {
+	out := make([]searchResult, len(results))
+	for i, r := range results {
+		out[i] = searchResult{
+			Ref:       refName(r.Entry),
+			Namespace: r.Ref.Namespace,
+			Name:      kebabName(r.Ref.Name),
+			Score:     r.Score,
+			Distance:  r.Distance,
+			Style:     r.Record.Style,
+			Category:  r.Record.Category,
+			Tags:      r.Record.Tags,
+			Path:      r.Record.Path,
+			Version:   r.Version,
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// one reference per line, for piping into fzf or xargs
+func printNames(w io.Writer, results []search.Result) error {
+	for _, r := range results {
+		_, err := fmt.Fprintln(w, refName(r.Entry))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var searchLimit *int
+var searchMaxDistance *int
+var searchFormat *string
+
+func init() {
+	searchLimit = searchCmd.Flags().Int(
+		"limit", 10, "the maximum number of results, 0 for all of them",
+	)
+	searchMaxDistance = searchCmd.Flags().Int(
+		"max-distance", 0, "leave out results further than this levenshtein distance from the query, 0 for no limit",
+	)
+	searchFormat = searchCmd.Flags().StringP(
+		"format", "f", "table", "the output format, supported formats: [table, json, names]",
+	)
+	rootCmd.AddCommand(searchCmd)
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "search the icons by name, tags and category",
+	Long: `search the icons by name, tags and category, queries can be narrowed with
+  style:fill          only icons of a style
+  category:arrows     only icons in a category (also cat:)
+  tag:trash           only icons with a tag
+  lib:ri              only icons of a library
+  version:>=3.0       only icons of libraries in a version range
+  -word               leave out icons with a word in their name or tags
+  "some words"        only icons containing the exact phrase`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printer, has := searchFormats[*searchFormat]
+		if !has {
+			log.Fatalf("unsupported output format %s", *searchFormat)
+		}
+
+		err := Update(false)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var results []search.Result
+		for _, r := range rank(strings.Join(args, " "), allEntries()) {
+			if *searchMaxDistance > 0 && r.Distance > *searchMaxDistance {
+				continue
+			}
+			if *searchLimit > 0 && len(results) == *searchLimit {
+				break
+			}
+			results = append(results, r)
+		}
+
+		err = printer(os.Stdout, results)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"icon-cli/library"
+	"icon-cli/search"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// the furthest two fingerprints can be apart
+const MAX_VISUAL_DISTANCE = len(library.Fingerprint{}) * 255
+
+// the fingerprint of an installed icon or an svg file on disk
+func fingerprintOf(arg string) (library.Fingerprint, error) {
+	if strings.HasSuffix(strings.ToLower(arg), ".svg") {
+		data, err := os.ReadFile(arg)
+		if err == nil {
+			return library.NewFingerprint(data)
+		}
+	}
+
+	ref, has := resolveRef(library.ParseRef(arg), "")
+	if !has {
+		return library.Fingerprint{}, fmt.Errorf("there is no icon %s", arg)
+	}
+	fingerprint, has := refFingerprint(ref)
+	if !has {
+		return library.Fingerprint{}, fmt.Errorf("could not draw %s", arg)
+	}
+	return fingerprint, nil
+}
+
+func refFingerprint(ref library.Ref) (library.Fingerprint, bool) {
+	store, has := libraries[ref.Namespace]
+	if !has {
+		return library.Fingerprint{}, false
+	}
+	record, has := store.Data.Record(store.Data.Version, ref.Name)
+	if !has {
+		return library.Fingerprint{}, false
+	}
+	return store.Data.Fingerprint(record.Hash)
+}
+
+// the entries ordered by how much they look like the target, the score
+// is the similarity in percent and the distance the visual distance, self
+// is the icon the target is from, which is left out
+func similarEntries(target library.Fingerprint, self library.Ref, entries []search.Entry) []search.Result {
+	results := make([]search.Result, 0, len(entries))
+	for _, e := range entries {
+		if e.Ref == self {
+			continue
+		}
+		fingerprint, has := libraries[e.Ref.Namespace].Data.Fingerprint(e.Record.Hash)
+		if !has {
+			continue
+		}
+		distance := target.Distance(fingerprint)
+		results = append(results, search.Result{
+			Entry:    e,
+			Score:    100 * (1 - float64(distance)/float64(MAX_VISUAL_DISTANCE)),
+			Distance: distance,
+		})
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Ref.String() < results[j].Ref.String()
+	})
+	return results
+}
+
+var similarLimit *int
+var similarStyle *string
+var similarFormat *string
+
+func init() {
+	similarLimit = similarCmd.Flags().Int(
+		"limit", 10, "the maximum number of results, 0 for all of them",
+	)
+	similarStyle = similarCmd.Flags().String(
+		"style", "", "only list icons of a style, like fill",
+	)
+	similarFormat = similarCmd.Flags().StringP(
+		"format", "f", "table", "the output format, supported formats: [table, json, names]",
+	)
+	rootCmd.AddCommand(similarCmd)
+}
+
+var similarCmd = &cobra.Command{
+	Use:   "similar <icon|file.svg>",
+	Short: "list the icons that look like an icon",
+	Long: `list the icons that look like an installed icon or an svg file, nearest first,
+the score is how similar they are in percent`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printer, has := searchFormats[*similarFormat]
+		if !has {
+			log.Fatalf("unsupported output format %s", *similarFormat)
+		}
+
+		err := Update(false)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		target, err := fingerprintOf(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		self, _ := resolveRef(library.ParseRef(args[0]), "")
+
+		var results []search.Result
+		for _, r := range similarEntries(target, self, allEntries()) {
+			if *similarStyle != "" && !strings.EqualFold(r.Record.Style, *similarStyle) {
+				continue
+			}
+			if *similarLimit > 0 && len(results) == *similarLimit {
+				break
+			}
+			results = append(results, r)
+		}
+
+		err = printer(os.Stdout, results)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"icon-cli/common"
+	"icon-cli/library"
+	"icon-cli/search"
+	"testing"
+)
+
+func TestSimilarEntries(t *testing.T) {
+	svgs := map[library.TextCase]string{
+		"square":  `<svg viewBox="0 0 24 24"><path d="M4 4h16v16H4z"/></svg>`,
+		"smaller": `<svg viewBox="0 0 24 24"><path d="M5 5h14v14H5z"/></svg>`,
+		"bar":     `<svg viewBox="0 0 24 24"><path d="M0 0h24v4H0z"/></svg>`,
+	}
+	index := library.NewIndex()
+	for name, svg := range svgs {
+		index.AddIcon(name, library.Icon{Data: []byte(svg)})
+	}
+	store := common.NewStore("", library.Library{})
+	store.Data.Add("v1", index)
+	store.Data.Version = "v1"
+	libraries = map[string]*common.Store[library.Library]{"test": store}
+
+	var entries []search.Entry
+	for name := range svgs {
+		record, _ := store.Data.Record("v1", name)
+		entries = append(entries, search.Entry{
+			Ref:    library.Ref{Namespace: "test", Name: name},
+			Record: record,
+		})
+	}
+
+	self := library.Ref{Namespace: "test", Name: "square"}
+	target, has := refFingerprint(self)
+	if !has {
+		t.Fatal("expected the icon to have a fingerprint")
+	}
+	results := similarEntries(target, self, entries)
+	if len(results) != 2 {
+		t.Fatalf("expected the icon itself to be left out, got %v", results)
+	}
+	if results[0].Ref.Name != "smaller" || results[1].Ref.Name != "bar" {
+		t.Errorf("expected the nearest icon first, got %s and %s", results[0].Ref, results[1].Ref)
+	}
+}
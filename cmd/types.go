@@ -1,6 +1,28 @@
 package cmd
 
+type LibraryConfig struct {
+	// the prefix icons of this library are referenced with, like ri:arrow-left-line
+	Namespace string
+	Source    string
+	Location  string
+	// the registry npm packages are resolved against
+	Registry string
+	// only read icons inside this directory of the source's archive
+	Directory string
+	// the upstream tag to install, when set the weekly update check is skipped
+	Version string
+}
+
 type Config struct {
-	Source   string
-	Location string
+	Libraries []LibraryConfig
+	// never check for updates, only use the installed libraries
+	Offline bool
+
+	// Deprecated: the single library of older configs, it is moved
+	// into Libraries by Load
+	Source    string
+	Location  string
+	Registry  string
+	Directory string
+	Version   string
 }
@@ -1,92 +1,237 @@
 package cmd
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"icon-cli/common"
 	"icon-cli/library"
 	"log"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 )
 
+// how long the weekly check may take before the installed library is used
+const UPDATE_CHECK_TIMEOUT = 10 * time.Second
+
 const (
-	SOURCE_HTTP   = "http"
-	SOURCE_GITHUB = "github"
+	SOURCE_HTTP    = "http"
+	SOURCE_GITHUB  = "github"
+	SOURCE_LOCAL   = "local"
+	SOURCE_NPM     = "npm"
+	SOURCE_ICONIFY = "iconify"
 )
 
-var iconLibrary *common.Store[library.Library]
-var cfg *common.Store[Config]
+func NewProvider(config LibraryConfig) (library.Provider, error) {
+	switch config.Source {
+	case SOURCE_HTTP:
+		parsed, err := url.Parse(config.Location)
+		if err != nil {
+			return nil, err
+		}
+		return &library.HTTP{
+			Url:       parsed,
+			Directory: config.Directory,
+		}, nil
+	case SOURCE_GITHUB:
+		return &library.Github{
+			RepoPath:  config.Location,
+			Directory: config.Directory,
+		}, nil
+	case SOURCE_LOCAL:
+		return library.Local{
+			Root: config.Location,
+		}, nil
+	case SOURCE_ICONIFY:
+		parsed, err := url.Parse(config.Location)
+		if err != nil {
+			return nil, err
+		}
+		return &library.Iconify{
+			Url: parsed,
+		}, nil
+	case SOURCE_NPM:
+		return library.NPM{
+			Registry:  config.Registry,
+			Package:   config.Location,
+			Directory: config.Directory,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown source %s", config.Source)
+}
 
-func init() {
-	rootCmd.AddCommand(updateCmd)
+// the sources that can fetch a given tag, the others only serve what
+// they currently have
+var pinnableSources = map[string]bool{
+	SOURCE_GITHUB: true,
+	SOURCE_NPM:    true,
 }
 
-func Update(force bool) error {
-	log.Println("checking for icon library updates...")
+// pinning a source that ignores the tag would store its current content
+// under the pinned version
+func checkPin(source, version string) error {
+	if version != "" && !pinnableSources[source] {
+		return fmt.Errorf("%s libraries can't be pinned to a version, they only serve their current icons", source)
+	}
+	return nil
+}
 
-	cfg = common.NewStore(*configPath, Config{
-		Source:   SOURCE_GITHUB,
-		Location: "/Remix-Design/RemixIcon",
-	})
-	err := cfg.Load()
+// set when a library could not be checked for updates and the
+// installed version is used as is
+var staleReason error
+
+func Update(force bool) error {
+	err := Load()
 	if err != nil {
 		return err
 	}
 
-	iconLibrary = common.NewStore(*libPath, library.Library{})
-	err = iconLibrary.Load()
-	if err != nil {
-		return err
+	for _, config := range cfg.Data.Libraries {
+		err := updateLibrary(config, libraries[config.Namespace], force)
+		if err != nil {
+			return fmt.Errorf("%s: %w", config.Namespace, err)
+		}
 	}
+	return nil
+}
 
-	// * update every week
-	if !force && time.Since(iconLibrary.Data.LastUpdate) < time.Hour*168 {
+func updateLibrary(config LibraryConfig, store *common.Store[library.Library], force bool) error {
+	pinned := config.Version
+	if pinned != "" && store.Data.Installed(pinned) {
+		if store.Data.Version == pinned {
+			return nil
+		}
+		store.Data.Version = pinned
+		return store.Write()
+	}
+
+	if *offline || cfg.Data.Offline {
+		if !store.Data.Installed(store.Data.Version) {
+			return errors.New("offline mode is on but no icons have been installed yet")
+		}
 		return nil
 	}
 
-	var provider library.Provider
-	switch cfg.Data.Source {
-	case SOURCE_HTTP:
-		parsed, err := url.Parse(cfg.Data.Location)
+	// automatic checks fall back to the installed library when the
+	// network is unreachable, explicit updates still report the error
+	err := update(config, store, force)
+	if err != nil && !force && store.Data.Installed(store.Data.Version) {
+		log.Printf("could not update %s, using the installed library: %v", config.Namespace, err)
+		staleReason = err
+		// checked again next week rather than on every launch
+		store.Data.LastUpdate = time.Now()
+		return store.Write()
+	}
+	return err
+}
+
+func update(config LibraryConfig, store *common.Store[library.Library], force bool) error {
+	log.Printf("checking for %s updates...", config.Namespace)
+
+	pinned := config.Version
+	// * update every week, unless a version is pinned
+	if pinned == "" && !force && time.Since(store.Data.LastUpdate) < time.Hour*168 {
+		return nil
+	}
+
+	provider, err := NewProvider(config)
+	if err != nil {
+		return err
+	}
+
+	err = checkPin(config.Source, pinned)
+	if err != nil {
+		return err
+	}
+	tag := pinned
+	if tag == "" {
+		// an unreachable network shouldn't hold up the automatic check
+		ctx := context.Background()
+		if !force {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, UPDATE_CHECK_TIMEOUT)
+			defer cancel()
+		}
+		tag, err = provider.Latest(ctx)
 		if err != nil {
 			return err
 		}
-		provider = &library.HTTP{
-			Url: parsed,
-		}
-	case SOURCE_GITHUB:
-		provider = &library.Github{
-			RepoPath: cfg.Data.Location,
+		// versions that are already installed are only switched to through
+		// `versions use`, so rolling back sticks until a newer release appears
+		if store.Data.Installed(tag) {
+			store.Data.LastUpdate = time.Now()
+			return store.Write()
 		}
+		log.Printf("found new version %s, updating...", tag)
+	} else {
+		log.Printf("installing pinned version %s...", tag)
 	}
-	provider.Latest()
 
-	latest, err := provider.Latest()
+	index, err := provider.Pull(tag)
 	if err != nil {
 		return err
 	}
-	if iconLibrary.Data.Version == latest {
-		return nil
+	for _, c := range index.Collisions {
+		log.Printf(
+			"%d icons are named %s (%s), the others were stored as %s",
+			len(c.Paths), c.Name, strings.Join(c.Paths, ", "), strings.Join(c.Renamed, ", "),
+		)
 	}
 
-	log.Printf("found new version %s, updating...", latest)
-	data, err := provider.Pull(latest)
-	if err != nil {
-		return nil
+	previous, has := store.Data.Manifests[store.Data.Version]
+	manifest := store.Data.Add(tag, index)
+	if has {
+		diff := library.Compare(previous, manifest)
+		log.Printf(
+			"%s -> %s: %d added, %d removed, %d renamed, %d changed",
+			store.Data.Version, tag,
+			len(diff.Added), len(diff.Removed), len(diff.Renamed), len(diff.Changed),
+		)
 	}
 
-	iconLibrary.Data.Index = data
-	iconLibrary.Data.Version = latest
-	iconLibrary.Data.LastUpdate = time.Now()
-	return iconLibrary.Write()
+	store.Data.Version = tag
+	store.Data.LastUpdate = time.Now()
+	return store.Write()
+}
+
+var updateVersion *string
+var updateUnpin *bool
+
+func init() {
+	updateVersion = updateCmd.Flags().String(
+		"version", "", "pin the selected github or npm library to an upstream tag, disabling the weekly update check",
+	)
+	updateUnpin = updateCmd.Flags().Bool(
+		"unpin", false, "go back to following the latest release of the selected library",
+	)
+	rootCmd.AddCommand(updateCmd)
 }
 
 var updateCmd = &cobra.Command{
 	Use:   "update",
-	Short: "update the icon library",
+	Short: "update the icon libraries",
 	Run: func(cmd *cobra.Command, args []string) {
-		err := Update(true)
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if *updateVersion != "" || *updateUnpin {
+			err = checkPin(selected.Source, *updateVersion)
+			if err != nil {
+				log.Fatal(err)
+			}
+			selected.Version = *updateVersion
+			err = cfg.Write()
+			if err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		err = Update(true)
 		if err != nil {
 			log.Fatal(err)
 		}
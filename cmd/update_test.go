@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"icon-cli/common"
+	"icon-cli/library"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPinnedVersions(t *testing.T) {
+	for _, source := range []string{SOURCE_GITHUB, SOURCE_NPM} {
+		config := LibraryConfig{Source: source}
+		if err := configFields["version"].set(&Config{}, &config, "v1.0.0"); err != nil || config.Version != "v1.0.0" {
+			t.Errorf("expected %s libraries to be pinned, got %v", source, err)
+		}
+	}
+	for _, source := range []string{SOURCE_HTTP, SOURCE_LOCAL, SOURCE_ICONIFY} {
+		config := LibraryConfig{Source: source}
+		if err := configFields["version"].set(&Config{}, &config, "v1.0.0"); err == nil || config.Version != "" {
+			t.Errorf("expected pinning a %s library to be rejected", source)
+		}
+		// unpinning always works
+		if err := configFields["version"].set(&Config{}, &config, ""); err != nil {
+			t.Error(err)
+		}
+	}
+
+	config := LibraryConfig{Source: SOURCE_GITHUB, Version: "v1.0.0"}
+	if err := configFields["source"].set(&Config{}, &config, SOURCE_LOCAL); err == nil || config.Source != SOURCE_GITHUB {
+		t.Error("expected a pinned library not to switch to a source that can't be pinned")
+	}
+}
+
+func TestUpdateCheckedAt(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "home-line.svg"), []byte("<svg/>"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg = common.NewStore(filepath.Join(dir, "config.bin"), Config{})
+	store := common.NewStore(filepath.Join(dir, "icons.bin"), library.Library{})
+	config := LibraryConfig{Namespace: "test", Source: SOURCE_LOCAL, Location: dir}
+	err = update(config, store, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the latest version is already installed
+	store.Data.LastUpdate = time.Time{}
+	err = updateLibrary(config, store, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Data.LastUpdate.IsZero() {
+		t.Error("expected the check to be recorded when nothing was new")
+	}
+
+	// the source can't be reached, the installed library is used
+	store.Data.LastUpdate = time.Time{}
+	config.Source, config.Location = SOURCE_HTTP, filepath.Join(dir, "missing.tar.gz")
+	err = updateLibrary(config, store, false)
+	if err != nil || staleReason == nil {
+		t.Fatalf("expected to fall back to the installed library, got %v", err)
+	}
+	if store.Data.LastUpdate.IsZero() {
+		t.Error("expected the fallback to be recorded so the check waits a week")
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	versionsCmd.AddCommand(versionsListCmd)
+	versionsCmd.AddCommand(versionsUseCmd)
+	versionsCmd.AddCommand(versionsRemoveCmd)
+	versionsCmd.AddCommand(versionsCollisionsCmd)
+	rootCmd.AddCommand(versionsCmd)
+}
+
+var versionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "manage the installed versions of the icon library",
+}
+
+var versionsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the installed versions",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		for _, v := range iconLibrary.Data.Versions() {
+			marker := " "
+			if v == iconLibrary.Data.Version {
+				marker = "*"
+			}
+			note := ""
+			if !iconLibrary.Data.Installed(v) {
+				note = " (manifest only)"
+			}
+			if collisions := len(iconLibrary.Data.Collisions[v]); collisions > 0 {
+				note += fmt.Sprintf(" (%d name collisions, see `versions collisions %s`)", collisions, v)
+			}
+			fmt.Printf(
+				"%s %s\t%d icons%s\n",
+				marker, v, len(iconLibrary.Data.Manifests[v]), note,
+			)
+		}
+	},
+}
+
+var versionsUseCmd = &cobra.Command{
+	Use:   "use <version>",
+	Short: "switch to an installed version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if !iconLibrary.Data.Installed(args[0]) {
+			log.Fatalf("version %s is not installed", args[0])
+		}
+		iconLibrary.Data.Version = args[0]
+		err = iconLibrary.Write()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var versionsRemoveCmd = &cobra.Command{
+	Use:   "remove <version>",
+	Short: "remove an installed version",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		err = iconLibrary.Data.Remove(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = iconLibrary.Write()
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var versionsCollisionsCmd = &cobra.Command{
+	Use:   "collisions [version]",
+	Short: "list the icons that had the same name in a version",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		err := Load()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		version := iconLibrary.Data.Version
+		if len(args) > 0 {
+			version = args[0]
+		}
+		for _, c := range iconLibrary.Data.Collisions[version] {
+			fmt.Printf("%s\n  %s (kept the name)\n", kebabName(c.Name), c.Paths[0])
+			for i, renamed := range c.Renamed {
+				fmt.Printf("  %s -> %s\n", c.Paths[i+1], kebabName(renamed))
+			}
+		}
+	},
+}
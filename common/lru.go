@@ -0,0 +1,65 @@
+package common
+
+import (
+	"container/list"
+	"sync"
+)
+
+type lruEntry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// a cache that forgets the least recently used values past its capacity
+type LRU[K comparable, V any] struct {
+	capacity int
+	order    *list.List
+	entries  map[K]*list.Element
+
+	lock sync.Mutex
+}
+
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	return &LRU[K, V]{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  map[K]*list.Element{},
+		lock:     sync.Mutex{},
+	}
+}
+
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+
+	element, has := c.entries[key]
+	if !has {
+		var zero V
+		return zero, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(lruEntry[K, V]).value, true
+}
+
+func (c *LRU[K, V]) Add(key K, value V) {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+
+	if element, has := c.entries[key]; has {
+		element.Value = lruEntry[K, V]{key, value}
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[key] = c.order.PushFront(lruEntry[K, V]{key, value})
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(lruEntry[K, V]).key)
+	}
+}
+
+func (c *LRU[K, V]) Len() int {
+	defer c.lock.Unlock()
+	c.lock.Lock()
+	return c.order.Len()
+}
@@ -0,0 +1,25 @@
+package common
+
+import "testing"
+
+func TestLRU(t *testing.T) {
+	cache := NewLRU[string, int](2)
+	cache.Add("a", 1)
+	cache.Add("b", 2)
+	// reading a makes b the least recently used
+	if v, has := cache.Get("a"); !has || v != 1 {
+		t.Errorf("expected a to be cached, got %d", v)
+	}
+	cache.Add("c", 3)
+
+	if _, has := cache.Get("b"); has {
+		t.Error("expected b to be evicted")
+	}
+	if _, has := cache.Get("a"); !has {
+		t.Error("expected a to be kept")
+	}
+	cache.Add("c", 4)
+	if v, _ := cache.Get("c"); v != 4 || cache.Len() != 2 {
+		t.Errorf("expected c to be replaced in place, got %d with %d entries", v, cache.Len())
+	}
+}
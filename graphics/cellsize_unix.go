@@ -0,0 +1,20 @@
+//go:build !windows
+
+package graphics
+
+import (
+	"image"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// the size of a cell in pixels, from the size the terminal reports for
+// its window, or DEFAULT_CELL_SIZE when it doesn't
+func CellSize(tty *os.File) image.Point {
+	size, err := unix.IoctlGetWinsize(int(tty.Fd()), unix.TIOCGWINSZ)
+	if err != nil || size.Col == 0 || size.Row == 0 || size.Xpixel == 0 || size.Ypixel == 0 {
+		return DEFAULT_CELL_SIZE
+	}
+	return image.Pt(int(size.Xpixel/size.Col), int(size.Ypixel/size.Row))
+}
@@ -0,0 +1,11 @@
+package graphics
+
+import (
+	"image"
+	"os"
+)
+
+// windows consoles don't report their size in pixels
+func CellSize(tty *os.File) image.Point {
+	return DEFAULT_CELL_SIZE
+}
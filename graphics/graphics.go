@@ -0,0 +1,89 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"io"
+	"strings"
+)
+
+// a way of drawing images in a terminal
+type Protocol = string
+
+const (
+	// braille characters, which work everywhere
+	PROTOCOL_BRAILLE Protocol = "braille"
+	// upper half blocks in the 256 color palette, drawn by termdash like braille
+	PROTOCOL_HALFBLOCK Protocol = "halfblock"
+	PROTOCOL_KITTY     Protocol = "kitty"
+	PROTOCOL_ITERM     Protocol = "iterm"
+	PROTOCOL_SIXEL     Protocol = "sixel"
+	// detect the protocol from the environment
+	PROTOCOL_AUTO Protocol = "auto"
+)
+
+// the size of a cell in pixels of most terminals at their default font size
+var DEFAULT_CELL_SIZE = image.Pt(10, 20)
+
+var Protocols = []Protocol{
+	PROTOCOL_AUTO, PROTOCOL_BRAILLE, PROTOCOL_HALFBLOCK,
+	PROTOCOL_KITTY, PROTOCOL_ITERM, PROTOCOL_SIXEL,
+}
+
+// guesses what the terminal supports from the variables it sets, asking
+// the terminal itself would race with the ui reading its input
+func Detect(getenv func(string) string) Protocol {
+	term := getenv("TERM")
+	program := getenv("TERM_PROGRAM")
+
+	switch {
+	// multiplexers swallow the sequences unless they are wrapped, but
+	// still pass colors through
+	case getenv("TMUX") != "" || strings.HasPrefix(term, "screen"):
+		break
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty" ||
+		term == "xterm-ghostty" || program == "ghostty":
+		return PROTOCOL_KITTY
+	case program == "iTerm.app" || program == "WezTerm":
+		return PROTOCOL_ITERM
+	case strings.Contains(term, "sixel") || strings.HasPrefix(term, "foot") ||
+		program == "mlterm":
+		return PROTOCOL_SIXEL
+	}
+
+	if strings.Contains(term, "256color") {
+		return PROTOCOL_HALFBLOCK
+	}
+	return PROTOCOL_BRAILLE
+}
+
+// the escape sequence that draws an image over a rectangle of cells,
+// sixel images are drawn at their own size so they have to be scaled
+// to the cells beforehand
+func Encode(protocol Protocol, img image.Image, cells image.Point) ([]byte, error) {
+	switch protocol {
+	case PROTOCOL_KITTY:
+		return Kitty(img, cells)
+	case PROTOCOL_ITERM:
+		return ITerm(img, cells)
+	case PROTOCOL_SIXEL:
+		return Sixel(img), nil
+	}
+	return nil, fmt.Errorf("%s is not a graphics protocol", protocol)
+}
+
+// writes a sequence at a cell on the screen, leaving the cursor where it was
+func Place(w io.Writer, at image.Point, sequence []byte) error {
+	_, err := fmt.Fprintf(w, "\x1b7\x1b[%d;%dH%s\x1b8", at.Y+1, at.X+1, sequence)
+	return err
+}
+
+// removes the images a protocol leaves behind, only kitty keeps them
+// around once the cells under them are redrawn
+func Clear(w io.Writer, protocol Protocol) error {
+	if protocol != PROTOCOL_KITTY {
+		return nil
+	}
+	_, err := io.WriteString(w, kittyDeleteAll)
+	return err
+}
@@ -0,0 +1,104 @@
+package graphics
+
+import (
+	"bytes"
+	"flag"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "record the escape sequence fixtures again")
+
+// compares output against a recorded fixture in testdata
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		err := os.WriteFile(path, got, 0666)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s doesn't match the fixture\ngot:  %q\nwant: %q", name, got, want)
+	}
+}
+
+// a payload long enough to be split into several kitty chunks
+func payload() []byte {
+	data := make([]byte, KITTY_CHUNK)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	return data
+}
+
+// an 8x8 icon with two colors, a red square with a blue bar under it
+func testIcon() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	for y := 1; y < 5; y++ {
+		for x := 2; x < 6; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	for x := 0; x < 8; x++ {
+		img.Set(x, 6, color.NRGBA{B: 255, A: 255})
+	}
+	return img
+}
+
+func TestKitty(t *testing.T) {
+	golden(t, "kitty.txt", kittyFrames(payload(), image.Pt(20, 10)))
+	golden(t, "kitty_small.txt", kittyFrames([]byte("icon"), image.Pt(4, 2)))
+}
+
+func TestITerm(t *testing.T) {
+	golden(t, "iterm.txt", itermFile([]byte("icon"), image.Pt(4, 2)))
+}
+
+func TestSixel(t *testing.T) {
+	red := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			red.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	want := "\x1bP0;0;0q\"1;1;2;2#180;2;100;0;0#180BB-\x1b\\"
+	if got := string(Sixel(red)); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	golden(t, "sixel.txt", Sixel(testIcon()))
+}
+
+func TestDetect(t *testing.T) {
+	cases := []struct {
+		env      map[string]string
+		expected Protocol
+	}{
+		{map[string]string{"TERM": "xterm-kitty"}, PROTOCOL_KITTY},
+		{map[string]string{"TERM": "xterm-256color", "KITTY_WINDOW_ID": "1"}, PROTOCOL_KITTY},
+		{map[string]string{"TERM_PROGRAM": "iTerm.app"}, PROTOCOL_ITERM},
+		{map[string]string{"TERM": "foot"}, PROTOCOL_SIXEL},
+		{map[string]string{"TERM": "xterm-kitty", "TMUX": "/tmp/tmux"}, PROTOCOL_BRAILLE},
+		{map[string]string{"TERM": "xterm"}, PROTOCOL_BRAILLE},
+		{map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, PROTOCOL_BRAILLE},
+		{map[string]string{"TERM": "xterm-256color"}, PROTOCOL_HALFBLOCK},
+		{map[string]string{"TERM": "screen-256color", "TMUX": "/tmp/tmux"}, PROTOCOL_HALFBLOCK},
+	}
+	for _, c := range cases {
+		got := Detect(func(key string) string {
+			return c.env[key]
+		})
+		if got != c.expected {
+			t.Errorf("%v: expected %s, got %s", c.env, c.expected, got)
+		}
+	}
+}
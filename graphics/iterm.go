@@ -0,0 +1,26 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// draws an image as an iTerm2 inline image, scaled into cells
+func ITerm(img image.Image, cells image.Point) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	err := png.Encode(buffer, img)
+	if err != nil {
+		return nil, err
+	}
+	return itermFile(buffer.Bytes(), cells), nil
+}
+
+func itermFile(data []byte, cells image.Point) []byte {
+	return []byte(fmt.Sprintf(
+		"\x1b]1337;File=inline=1;size=%d;width=%d;height=%d;preserveAspectRatio=1:%s\a",
+		len(data), cells.X, cells.Y, base64.StdEncoding.EncodeToString(data),
+	))
+}
@@ -0,0 +1,61 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+)
+
+// the size of the base64 payload in each escape sequence, the protocol
+// allows at most 4096
+const KITTY_CHUNK = 4096
+
+// every preview reuses one image id, so drawing a new one can replace it
+const KITTY_IMAGE_ID = 1
+
+var (
+	kittyDelete    = fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", KITTY_IMAGE_ID)
+	kittyDeleteAll = "\x1b_Ga=d,d=A,q=2\x1b\\"
+)
+
+// draws an image with the kitty graphics protocol, scaled into cells
+func Kitty(img image.Image, cells image.Point) ([]byte, error) {
+	buffer := bytes.NewBuffer(nil)
+	err := png.Encode(buffer, img)
+	if err != nil {
+		return nil, err
+	}
+	return kittyFrames(buffer.Bytes(), cells), nil
+}
+
+// splits png data into the chunks kitty expects, replacing the image
+// drawn before
+func kittyFrames(data []byte, cells image.Point) []byte {
+	payload := base64.StdEncoding.EncodeToString(data)
+
+	out := bytes.NewBufferString(kittyDelete)
+	for first := true; first || len(payload) > 0; first = false {
+		chunk := payload
+		if len(chunk) > KITTY_CHUNK {
+			chunk = chunk[:KITTY_CHUNK]
+		}
+		payload = payload[len(chunk):]
+
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+		if first {
+			// q=2 keeps the terminal from answering, C=1 keeps the cursor still
+			fmt.Fprintf(
+				out, "\x1b_Ga=T,f=100,i=%d,q=2,C=1,c=%d,r=%d,m=%d;%s\x1b\\",
+				KITTY_IMAGE_ID, cells.X, cells.Y, more, chunk,
+			)
+			continue
+		}
+		fmt.Fprintf(out, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+	}
+	return out.Bytes()
+}
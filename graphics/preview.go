@@ -0,0 +1,244 @@
+package graphics
+
+import (
+	"fmt"
+	"icon-cli/library"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+)
+
+type Background int
+
+const (
+	// leaves the terminal's own background
+	BACKGROUND_NONE Background = iota
+	BACKGROUND_LIGHT
+	BACKGROUND_DARK
+	BACKGROUND_CHECKER
+)
+
+var backgroundNames = map[Background]string{
+	BACKGROUND_NONE:    "no background",
+	BACKGROUND_LIGHT:   "light",
+	BACKGROUND_DARK:    "dark",
+	BACKGROUND_CHECKER: "checkerboard",
+}
+
+var (
+	LIGHT = color.NRGBA{R: 0xf5, G: 0xf5, B: 0xf5, A: 0xff}
+	DARK  = color.NRGBA{R: 0x1e, G: 0x1e, B: 0x1e, A: 0xff}
+	// the squares of the checkerboard alternate between light and this
+	CHECKER = color.NRGBA{R: 0xcc, G: 0xcc, B: 0xcc, A: 0xff}
+	// the lines between the pixels of the pixel grid
+	GRID_LINE = color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff}
+)
+
+// the steps each preview control goes through
+var (
+	PREVIEW_ZOOMS  = []float64{1, 2, 4, 0.5}
+	PREVIEW_GRIDS  = []int{0, 16, 20, 24}
+	PREVIEW_COLORS = []color.Color{
+		nil,
+		color.NRGBA{R: 0x00, G: 0x00, B: 0x00, A: 0xff},
+		color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		color.NRGBA{R: 0xe5, G: 0x39, B: 0x35, A: 0xff},
+		color.NRGBA{R: 0x1e, G: 0x88, B: 0xe5, A: 0xff},
+		color.NRGBA{R: 0x43, G: 0xa0, B: 0x47, A: 0xff},
+	}
+)
+
+// how an icon is drawn in the preview pane
+type Preview struct {
+	// the size of the preview in pixels
+	Res int
+	// how large the icon is in the preview, it is cropped past 1
+	Zoom       float64
+	Background Background
+	// replaces the color of the icon, nil keeps the colors it is drawn in
+	Color color.Color
+	// draws the icon at this size in pixels, scaled up with the pixels
+	// outlined, 0 to draw it at full size
+	Grid int
+}
+
+func NewPreview(res int) Preview {
+	return Preview{Res: res, Zoom: 1}
+}
+
+// the steps of a control after the current one, wrapping around
+func next[T comparable](steps []T, current T) T {
+	for i, s := range steps {
+		if s == current {
+			return steps[(i+1)%len(steps)]
+		}
+	}
+	return steps[0]
+}
+
+func (p Preview) NextZoom() Preview {
+	p.Zoom = next(PREVIEW_ZOOMS, p.Zoom)
+	return p
+}
+
+func (p Preview) NextBackground() Preview {
+	p.Background = (p.Background + 1) % Background(len(backgroundNames))
+	return p
+}
+
+func (p Preview) NextColor() Preview {
+	for i, c := range PREVIEW_COLORS {
+		if c == p.Color {
+			p.Color = PREVIEW_COLORS[(i+1)%len(PREVIEW_COLORS)]
+			return p
+		}
+	}
+	p.Color = PREVIEW_COLORS[0]
+	return p
+}
+
+func (p Preview) NextGrid() Preview {
+	p.Grid = next(PREVIEW_GRIDS, p.Grid)
+	return p
+}
+
+// a short summary of the controls that aren't at their defaults
+func (p Preview) String() string {
+	var parts []string
+	if p.Zoom != 1 {
+		parts = append(parts, fmt.Sprintf("%g%%", p.Zoom*100))
+	}
+	if p.Background != BACKGROUND_NONE {
+		parts = append(parts, backgroundNames[p.Background])
+	}
+	if p.Color != nil {
+		c := color.NRGBAModel.Convert(p.Color).(color.NRGBA)
+		parts = append(parts, fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+	}
+	if p.Grid > 0 {
+		parts = append(parts, fmt.Sprintf("%dpx", p.Grid))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return " " + strings.Join(parts, " · ") + " "
+}
+
+// identifies what a preview looks like, for caching renders
+func (p Preview) Key() string {
+	return fmt.Sprintf("%d %g %d %v %d", p.Res, p.Zoom, p.Background, p.Color, p.Grid)
+}
+
+// keeps the alpha of every pixel but replaces its color
+func recolor(img *image.NRGBA, c color.Color) {
+	fill := color.NRGBAModel.Convert(c).(color.NRGBA)
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2] = fill.R, fill.G, fill.B
+	}
+}
+
+// scales an image up by a whole factor without smoothing it
+func nearest(img *image.NRGBA, scale int) *image.NRGBA {
+	size := img.Bounds().Size()
+	scaled := image.NewNRGBA(image.Rect(0, 0, size.X*scale, size.Y*scale))
+	for y := 0; y < size.Y*scale; y++ {
+		for x := 0; x < size.X*scale; x++ {
+			scaled.SetNRGBA(x, y, img.NRGBAAt(x/scale, y/scale))
+		}
+	}
+	return scaled
+}
+
+func (p Preview) background(size int) image.Image {
+	switch p.Background {
+	case BACKGROUND_LIGHT:
+		return image.NewUniform(LIGHT)
+	case BACKGROUND_DARK:
+		return image.NewUniform(DARK)
+	case BACKGROUND_CHECKER:
+		checker := image.NewNRGBA(image.Rect(0, 0, size, size))
+		square := size/16 + 1
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				c := LIGHT
+				if (x/square+y/square)%2 == 1 {
+					c = CHECKER
+				}
+				checker.SetNRGBA(x, y, c)
+			}
+		}
+		return checker
+	}
+	return nil
+}
+
+// draws an svg as the preview shows it
+func (p Preview) Render(data []byte) (image.Image, error) {
+	size := int(float64(p.Res) * p.Zoom)
+	drawAt := size
+	if p.Grid > 0 {
+		drawAt = p.Grid
+	}
+	rgba, err := library.Rasterize(data, drawAt)
+	if err != nil {
+		return nil, err
+	}
+	icon := image.NewNRGBA(rgba.Bounds())
+	draw.Draw(icon, icon.Bounds(), rgba, image.Point{}, draw.Src)
+	if p.Color != nil {
+		recolor(icon, p.Color)
+	}
+
+	scale := 1
+	if p.Grid > 0 {
+		scale = size / p.Grid
+		if scale < 1 {
+			scale = 1
+		}
+		icon = nearest(icon, scale)
+	}
+
+	// centers the icon, cropping it when it is zoomed past the preview
+	out := image.NewNRGBA(image.Rect(0, 0, p.Res, p.Res))
+	if background := p.background(p.Res); background != nil {
+		draw.Draw(out, out.Bounds(), background, image.Point{}, draw.Src)
+	}
+	offset := image.Pt((p.Res-icon.Bounds().Dx())/2, (p.Res-icon.Bounds().Dy())/2)
+	draw.Draw(out, icon.Bounds().Add(offset), icon, image.Point{}, draw.Over)
+
+	if p.Grid > 0 {
+		line := image.NewUniform(GRID_LINE)
+		for i := 0; i <= p.Grid; i++ {
+			at := offset.Add(image.Pt(i*scale, i*scale))
+			area := icon.Bounds().Add(offset)
+			draw.Draw(out, image.Rect(at.X, area.Min.Y, at.X+1, area.Max.Y).Intersect(out.Bounds()), line, image.Point{}, draw.Src)
+			draw.Draw(out, image.Rect(area.Min.X, at.Y, area.Max.X, at.Y+1).Intersect(out.Bounds()), line, image.Point{}, draw.Src)
+		}
+	}
+	return out, nil
+}
+
+// draws images next to each other with a gap between them, on a
+// transparent background
+func SideBySide(imgs []image.Image, gap int) image.Image {
+	width, height := 0, 0
+	for i, img := range imgs {
+		if i > 0 {
+			width += gap
+		}
+		width += img.Bounds().Dx()
+		if img.Bounds().Dy() > height {
+			height = img.Bounds().Dy()
+		}
+	}
+	out := image.NewNRGBA(image.Rect(0, 0, width, height))
+	x := 0
+	for _, img := range imgs {
+		bounds := img.Bounds()
+		at := image.Pt(x, (height-bounds.Dy())/2)
+		draw.Draw(out, image.Rectangle{at, at.Add(bounds.Size())}, img, bounds.Min, draw.Src)
+		x += bounds.Dx() + gap
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package graphics
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// a square covering the middle half of the icon
+const squareSVG = `<svg viewBox="0 0 24 24"><path d="M6 6h12v12H6z"/></svg>`
+
+func TestPreview(t *testing.T) {
+	at := func(img image.Image, x, y int) color.NRGBA {
+		return color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+	}
+	render := func(p Preview) image.Image {
+		img, err := p.Render([]byte(squareSVG))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if img.Bounds().Dx() != p.Res || img.Bounds().Dy() != p.Res {
+			t.Fatalf("expected a %dpx preview, got %v", p.Res, img.Bounds())
+		}
+		return img
+	}
+
+	p := NewPreview(48)
+	plain := render(p)
+	if at(plain, 0, 0).A != 0 || at(plain, 24, 24).A != 255 {
+		t.Error("expected the square in the middle of a transparent preview")
+	}
+
+	// zoomed in 4 times, the square covers the whole preview
+	if zoomed := render(p.NextZoom().NextZoom()); at(zoomed, 0, 0).A != 255 {
+		t.Error("expected the zoomed square to be cropped to the preview")
+	}
+
+	red := p.NextColor().NextColor().NextColor()
+	if c := at(render(red), 24, 24); c.R != 0xe5 || c.G != 0x39 {
+		t.Errorf("expected the icon in red, got %v", c)
+	}
+
+	if c := at(render(p.NextBackground()), 0, 0); c != LIGHT {
+		t.Errorf("expected a light background, got %v", c)
+	}
+
+	grid := p.NextGrid()
+	if grid.Grid != 16 {
+		t.Fatalf("expected the first grid to be 16px, got %d", grid.Grid)
+	}
+	// 16px scaled 3 times to 48px, the lines are between the pixels
+	gridded := render(grid)
+	if at(gridded, 3, 10) != GRID_LINE || at(gridded, 10, 3) != GRID_LINE {
+		t.Error("expected grid lines between the pixels")
+	}
+	if at(gridded, 4, 10).A != 0 {
+		t.Error("expected no grid line inside a pixel")
+	}
+
+	if s := red.NextZoom().NextGrid().String(); s != " 200% · #e53935 · 16px " {
+		t.Errorf("unexpected summary %q", s)
+	}
+	if s := p.String(); s != "" {
+		t.Errorf("expected no summary at the defaults, got %q", s)
+	}
+}
+
+func TestSideBySide(t *testing.T) {
+	a := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	b := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	b.Set(0, 0, color.NRGBA{R: 255, A: 255})
+	out := SideBySide([]image.Image{a, b}, 2)
+	if out.Bounds() != image.Rect(0, 0, 10, 4) {
+		t.Fatalf("unexpected bounds %v", out.Bounds())
+	}
+	// the shorter image is centered vertically after the gap
+	if _, _, _, alpha := out.At(6, 1).RGBA(); alpha == 0 {
+		t.Errorf("expected the second image at 6,1")
+	}
+}
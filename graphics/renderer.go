@@ -0,0 +1,124 @@
+package graphics
+
+import (
+	"context"
+	"icon-cli/common"
+	"image"
+	"log"
+	"sync"
+)
+
+// what a render is cached under, the style holds the size of the
+// preview and how it is drawn
+type RenderKey struct {
+	Name  string
+	Style string
+}
+
+type renderJob struct {
+	key     RenderKey
+	preview Preview
+}
+
+// renders previews on a pool of workers so moving through the list never
+// waits on a render, finished renders are kept in an lru cache
+type Renderer struct {
+	cache *common.LRU[RenderKey, image.Image]
+	lock  sync.Mutex
+	wake  *sync.Cond
+
+	// the renders still to do, the wanted one first, then its neighbours
+	queue []renderJob
+	// the render the ui is waiting for, others are only prefetched
+	wanted    RenderKey
+	rendering map[RenderKey]bool
+
+	// draws the final frame, which is what is cached, so anything done to
+	// a render before it is shown belongs in here
+	Render func(name string, p Preview) (image.Image, error)
+	// called with the wanted render once it is done, the lock is held so
+	// a render that finishes late can't replace a newer one, it must not
+	// call back into the renderer
+	OnRender func(name string, img image.Image)
+}
+
+func NewRenderer(capacity int, render func(string, Preview) (image.Image, error)) *Renderer {
+	r := &Renderer{
+		cache:     common.NewLRU[RenderKey, image.Image](capacity),
+		lock:      sync.Mutex{},
+		rendering: map[RenderKey]bool{},
+		Render:    render,
+	}
+	r.wake = sync.NewCond(&r.lock)
+	return r
+}
+
+// starts the workers, they stop when the context is cancelled
+func (r *Renderer) Start(ctx context.Context, workers int) {
+	for i := 0; i < workers; i++ {
+		go r.work(ctx)
+	}
+	go func() {
+		<-ctx.Done()
+		r.lock.Lock()
+		r.wake.Broadcast()
+		r.lock.Unlock()
+	}()
+}
+
+// asks for an icon to be rendered and its neighbours to be prefetched,
+// renders asked for before that haven't started yet are dropped, a cached
+// render is handed to OnRender right away
+func (r *Renderer) Request(name string, p Preview, neighbours []string) {
+	style := p.Key()
+	key := RenderKey{Name: name, Style: style}
+
+	r.lock.Lock()
+	r.wanted = key
+	r.queue = r.queue[:0]
+	for _, n := range append([]string{name}, neighbours...) {
+		k := RenderKey{Name: n, Style: style}
+		if _, has := r.cache.Get(k); has || r.rendering[k] {
+			continue
+		}
+		r.queue = append(r.queue, renderJob{key: k, preview: p})
+	}
+	r.wake.Broadcast()
+	if img, has := r.cache.Get(key); has && r.OnRender != nil {
+		r.OnRender(name, img)
+	}
+	r.lock.Unlock()
+}
+
+func (r *Renderer) work(ctx context.Context) {
+	for {
+		r.lock.Lock()
+		for len(r.queue) == 0 && ctx.Err() == nil {
+			r.wake.Wait()
+		}
+		if ctx.Err() != nil {
+			r.lock.Unlock()
+			return
+		}
+		job := r.queue[0]
+		key := job.key
+		r.queue = r.queue[1:]
+		r.rendering[key] = true
+		r.lock.Unlock()
+
+		img, err := r.Render(key.Name, job.preview)
+		if err != nil {
+			log.Println(err)
+		}
+
+		r.lock.Lock()
+		delete(r.rendering, key)
+		if err == nil {
+			r.cache.Add(key, img)
+			if key == r.wanted && r.OnRender != nil {
+				r.OnRender(key.Name, img)
+			}
+		}
+		r.lock.Unlock()
+	}
+}
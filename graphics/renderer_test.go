@@ -0,0 +1,70 @@
+package graphics
+
+import (
+	"context"
+	"image"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRenderer(t *testing.T) {
+	lock := sync.Mutex{}
+	rendered := map[string]int{}
+	// holds renders back until the test lets them through
+	gate := make(chan struct{})
+
+	r := NewRenderer(8, func(name string, p Preview) (image.Image, error) {
+		<-gate
+		lock.Lock()
+		rendered[name]++
+		lock.Unlock()
+		return image.NewRGBA(image.Rect(0, 0, p.Res, p.Res)), nil
+	})
+	shown := make(chan string, 8)
+	r.OnRender = func(name string, img image.Image) {
+		shown <- name
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r.Start(ctx, 1)
+
+	p := NewPreview(4)
+	r.Request("a", p, nil)
+	// b replaces a's neighbours before they start, only c is wanted
+	r.Request("b", p, []string{"x"})
+	r.Request("c", p, []string{"d"})
+	close(gate)
+
+	select {
+	case name := <-shown:
+		if name != "c" {
+			t.Errorf("expected only the wanted render to be shown, got %s", name)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the wanted render was never shown")
+	}
+
+	// the neighbour is prefetched, so asking for it is answered from the cache
+	deadline := time.Now().Add(time.Second)
+	for {
+		lock.Lock()
+		done := rendered["d"] == 1
+		lock.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	r.Request("d", p, nil)
+	if name := <-shown; name != "d" {
+		t.Errorf("expected the prefetched render, got %s", name)
+	}
+
+	lock.Lock()
+	defer lock.Unlock()
+	if rendered["x"] != 0 || rendered["b"] != 0 || rendered["d"] != 1 {
+		t.Errorf("expected stale renders to be dropped and cached ones reused, got %v", rendered)
+	}
+}
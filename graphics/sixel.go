@@ -0,0 +1,122 @@
+package graphics
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// the levels of each channel in the sixel palette, which makes for the
+// 216 web safe colors
+const SIXEL_LEVELS = 6
+
+// the palette index of a pixel, -1 when it is mostly transparent
+func sixelColor(c color.Color) int {
+	pixel := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if pixel.A < 128 {
+		return -1
+	}
+	level := func(v uint8) int {
+		return (int(v)*(SIXEL_LEVELS-1) + 127) / 255
+	}
+	return level(pixel.R)*SIXEL_LEVELS*SIXEL_LEVELS + level(pixel.G)*SIXEL_LEVELS + level(pixel.B)
+}
+
+// writes a run of the same sixel, compressing runs longer than 3
+func writeSixelRun(out *bytes.Buffer, sixel byte, run int) {
+	if run > 3 {
+		fmt.Fprintf(out, "!%d%c", run, sixel)
+		return
+	}
+	for i := 0; i < run; i++ {
+		out.WriteByte(sixel)
+	}
+}
+
+// draws an image with sixels at its own size, transparent pixels are
+// painted in the background color so they cover what was drawn before
+func Sixel(img image.Image) []byte {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	indexes := make([]int, width*height)
+	used := map[int]bool{}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			index := sixelColor(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			indexes[y*width+x] = index
+			if index >= 0 {
+				used[index] = true
+			}
+		}
+	}
+	palette := make([]int, 0, len(used))
+	for index := range used {
+		palette = append(palette, index)
+	}
+	sort.Ints(palette)
+
+	out := bytes.NewBuffer(nil)
+	// 1:1 pixels, with the size given up front
+	fmt.Fprintf(out, "\x1bP0;0;0q\"1;1;%d;%d", width, height)
+	percent := func(level int) int {
+		return level * 100 / (SIXEL_LEVELS - 1)
+	}
+	for _, index := range palette {
+		fmt.Fprintf(
+			out, "#%d;2;%d;%d;%d", index,
+			percent(index/(SIXEL_LEVELS*SIXEL_LEVELS)),
+			percent(index/SIXEL_LEVELS%SIXEL_LEVELS),
+			percent(index%SIXEL_LEVELS),
+		)
+	}
+
+	// each band is 6 pixels tall, drawn once for every color in it
+	for top := 0; top < height; top += 6 {
+		first := true
+		for _, index := range palette {
+			row := make([]byte, width)
+			present := false
+			for x := 0; x < width; x++ {
+				bits := 0
+				for dy := 0; dy < 6 && top+dy < height; dy++ {
+					if indexes[(top+dy)*width+x] == index {
+						bits |= 1 << dy
+					}
+				}
+				if bits != 0 {
+					present = true
+				}
+				row[x] = byte(63 + bits)
+			}
+			if !present {
+				continue
+			}
+			// returns to the start of the band for the next color
+			if !first {
+				out.WriteByte('$')
+			}
+			first = false
+
+			fmt.Fprintf(out, "#%d", index)
+			// trailing empty sixels are left out
+			end := len(row)
+			for end > 0 && row[end-1] == 63 {
+				end--
+			}
+			run := 0
+			for x := 0; x < end; x++ {
+				run++
+				if x+1 == end || row[x+1] != row[x] {
+					writeSixelRun(out, row[x], run)
+					run = 0
+				}
+			}
+		}
+		out.WriteByte('-')
+	}
+	out.WriteString("\x1b\\")
+	return out.Bytes()
+}
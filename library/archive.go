@@ -0,0 +1,186 @@
+package library
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/url"
+	"os"
+
+	"github.com/carlmjohnson/requests"
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zipMagic  = []byte("PK\x03\x04")
+	tarMagic  = []byte("ustar")
+)
+
+// downloads the content at a url, urls without a scheme or with
+// the file scheme are read from disk
+func Fetch(ctx context.Context, loc *url.URL) ([]byte, error) {
+	if loc.Scheme == "" || loc.Scheme == "file" {
+		return os.ReadFile(loc.Path)
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	err := requests.
+		URL(loc.String()).
+		ToBytesBuffer(buffer).
+		Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func GenerateFromArchive(loc *url.URL, dir string) (Index, error) {
+	data, err := Fetch(context.Background(), loc)
+	if err != nil {
+		return Index{}, err
+	}
+	return ReadArchiveDir(data, dir)
+}
+
+// reads the icons out of a tar, tar.gz or zip archive, the type is
+// detected from the content rather than the file extension
+func ReadArchive(data []byte) (Index, error) {
+	return ReadArchiveDir(data, "")
+}
+
+// whether a file is inside dir, which is relative to the top level
+// folder archives usually wrap their contents in
+func inDirectory(path, dir string) bool {
+	if dir == "" {
+		return true
+	}
+	segments := NewPath(path)
+	if len(segments) < 2 {
+		return false
+	}
+	prefix := NewPath(dir)
+	if len(segments)-1 <= len(prefix) {
+		return false
+	}
+	for i, p := range prefix {
+		if segments[i+1] != p {
+			return false
+		}
+	}
+	return true
+}
+
+// like ReadArchive, but only reads the files inside dir
+func ReadArchiveDir(data []byte, dir string) (Index, error) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return Index{}, err
+		}
+		uncompressed, err := io.ReadAll(gzipReader)
+		if err != nil {
+			return Index{}, err
+		}
+		return ReadArchiveDir(uncompressed, dir)
+	case bytes.HasPrefix(data, zipMagic):
+		return readZip(data, dir)
+	case len(data) >= 262 && bytes.Equal(data[257:262], tarMagic):
+		return readTar(data, dir)
+	}
+	return Index{}, errors.New("unsupported archive type")
+}
+
+func readTar(data []byte, dir string) (Index, error) {
+	index := NewIndex()
+	var tags []byte
+
+	tarReader := tar.NewReader(bytes.NewReader(data))
+	for {
+		header, err := tarReader.Next()
+		if err != nil {
+			break
+		}
+
+		tagsFile := IsTagsFile(header.Name)
+		if !tagsFile && (!IsIconFile(header.Name) || !inDirectory(header.Name, dir)) {
+			continue
+		}
+
+		switch header.Typeflag {
+		case tar.TypeReg:
+			buffer := bytes.NewBuffer(nil)
+			_, err := io.Copy(buffer, tarReader)
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			if tagsFile {
+				tags = buffer.Bytes()
+				continue
+			}
+			if !IsSVGFont(buffer.Bytes()) {
+				index.Add(header.Name, buffer.Bytes())
+			}
+		}
+	}
+
+	applyTags(&index, tags)
+	return index, nil
+}
+
+func readZip(data []byte, dir string) (Index, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return Index{}, err
+	}
+
+	index := NewIndex()
+	var tags []byte
+	for _, f := range zipReader.File {
+		tagsFile := IsTagsFile(f.Name)
+		if f.FileInfo().IsDir() || (!tagsFile && (!IsIconFile(f.Name) || !inDirectory(f.Name, dir))) {
+			continue
+		}
+
+		src, err := f.Open()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+		content, err := io.ReadAll(src)
+		src.Close()
+		if err != nil {
+			log.Println(err)
+			continue
+		}
+
+		if tagsFile {
+			tags = content
+			continue
+		}
+		if !IsSVGFont(content) {
+			index.Add(f.Name, content)
+		}
+	}
+
+	applyTags(&index, tags)
+	return index, nil
+}
+
+// tags are nice to have, a broken tags file shouldn't stop an update
+func applyTags(index *Index, tags []byte) {
+	if tags == nil {
+		return
+	}
+	err := index.ApplyTags(tags)
+	if err != nil {
+		log.Println(err)
+	}
+}
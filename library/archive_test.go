@@ -0,0 +1,89 @@
+package library
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"testing"
+)
+
+var archiveFiles = map[string]string{
+	"RemixIcon-3.0.0/icons/Arrows/arrow-left-line.svg": "<svg/>",
+	"RemixIcon-3.0.0/icons/Buildings/home-line.svg":    "<svg></svg>",
+	// shipped next to the icons but not icons themselves
+	"RemixIcon-3.0.0/README.md":           "# RemixIcon",
+	"RemixIcon-3.0.0/fonts/remixicon.css": ".ri-arrow-left-line:before {}",
+	"RemixIcon-3.0.0/fonts/remixicon.ttf": "\x00\x01\x00\x00",
+	"RemixIcon-3.0.0/fonts/remixicon.svg": `<svg><defs><font id="remixicon"><glyph/></font></defs></svg>`,
+}
+
+func testTar(t *testing.T) []byte {
+	buffer := bytes.NewBuffer(nil)
+	writer := tar.NewWriter(buffer)
+	for name, content := range archiveFiles {
+		err := writer.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0666,
+			Size:     int64(len(content)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write([]byte(content))
+	}
+	writer.Close()
+	return buffer.Bytes()
+}
+
+func testZip(t *testing.T) []byte {
+	buffer := bytes.NewBuffer(nil)
+	writer := zip.NewWriter(buffer)
+	for name, content := range archiveFiles {
+		f, err := writer.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	writer.Close()
+	return buffer.Bytes()
+}
+
+func TestReadArchive(t *testing.T) {
+	tarGz := bytes.NewBuffer(nil)
+	gzipWriter := gzip.NewWriter(tarGz)
+	gzipWriter.Write(testTar(t))
+	gzipWriter.Close()
+
+	archives := map[string][]byte{
+		"tar":    testTar(t),
+		"tar.gz": tarGz.Bytes(),
+		"zip":    testZip(t),
+	}
+	for kind, data := range archives {
+		t.Run(kind, func(t *testing.T) {
+			lib, err := ReadArchive(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(lib.Icons) != 2 || string(lib.Icons["arrow left line"].Data) != "<svg/>" {
+				t.Errorf("unexpected icons %v", lib.Icons)
+			}
+			if lib.Icons["arrow left line"].Category != "Arrows" {
+				t.Errorf("category was not kept: %v", lib.Icons["arrow left line"].Record)
+			}
+			// the font assets share the name remixicon, they used to be
+			// renamed and reported as colliding icons
+			if len(lib.Collisions) != 0 {
+				t.Errorf("expected no collisions, got %v", lib.Collisions)
+			}
+		})
+	}
+
+	_, err := ReadArchive([]byte("not an archive"))
+	if err == nil {
+		t.Error("expected an error for unknown archive types")
+	}
+}
@@ -0,0 +1,110 @@
+package library
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// the content hash of every icon in a version, keyed by name
+type Manifest = map[TextCase]string
+
+func Hash(data []byte) string {
+	hasher := sha256.New()
+	hasher.Write(data)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func NewManifest(index map[TextCase][]byte) Manifest {
+	manifest := Manifest{}
+	for name, data := range index {
+		manifest[name] = Hash(data)
+	}
+	return manifest
+}
+
+type Diff struct {
+	Added   []TextCase
+	Removed []TextCase
+	Changed []TextCase
+	// removed icons whose exact content reappears under a new name
+	Renamed map[TextCase]TextCase
+}
+
+func Compare(from, to Manifest) Diff {
+	diff := Diff{Renamed: map[TextCase]TextCase{}}
+
+	added := map[string][]TextCase{}
+	for name, hash := range to {
+		old, has := from[name]
+		if !has {
+			added[hash] = append(added[hash], name)
+			continue
+		}
+		if old != hash {
+			diff.Changed = append(diff.Changed, name)
+		}
+	}
+	for _, names := range added {
+		sort.Strings(names)
+	}
+
+	removed := make([]TextCase, 0, len(from))
+	for name := range from {
+		if _, has := to[name]; !has {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+
+	for _, name := range removed {
+		hash := from[name]
+		candidates := added[hash]
+		if len(candidates) == 0 {
+			diff.Removed = append(diff.Removed, name)
+			continue
+		}
+		diff.Renamed[name] = candidates[0]
+		added[hash] = candidates[1:]
+	}
+
+	for _, names := range added {
+		diff.Added = append(diff.Added, names...)
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Changed)
+	return diff
+}
+
+// returns up to max names in the target manifest that look like
+// the given (removed) icon, closest first
+func Suggest(name TextCase, to Manifest, max int) []TextCase {
+	targets := make([]string, 0, len(to))
+	for k := range to {
+		targets = append(targets, k)
+	}
+
+	ranks := fuzzy.RankFindNormalizedFold(name, targets)
+	if ranks.Len() == 0 {
+		// the name might have lost a word, so try matching the other way around
+		for _, target := range targets {
+			if fuzzy.MatchNormalizedFold(target, name) {
+				ranks = append(ranks, fuzzy.Rank{
+					Source:   name,
+					Target:   target,
+					Distance: fuzzy.LevenshteinDistance(name, target),
+				})
+			}
+		}
+	}
+	sort.Sort(ranks)
+
+	var result []TextCase
+	for i := 0; i < len(ranks) && i < max; i++ {
+		result = append(result, ranks[i].Target)
+	}
+	return result
+}
@@ -0,0 +1,35 @@
+package library
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	from := NewManifest(map[TextCase][]byte{
+		"arrow left line": []byte("a"),
+		"home line":       []byte("b"),
+		"delete bin line": []byte("c"),
+		"old line":        []byte("d"),
+	})
+	to := NewManifest(map[TextCase][]byte{
+		"arrow left line":   []byte("a"),
+		"home line":         []byte("b2"),
+		"delete bin 2 line": []byte("c"),
+		"new line":          []byte("e"),
+	})
+
+	diff := Compare(from, to)
+	if !reflect.DeepEqual(diff.Added, []TextCase{"new line"}) {
+		t.Errorf("added: %v", diff.Added)
+	}
+	if !reflect.DeepEqual(diff.Removed, []TextCase{"old line"}) {
+		t.Errorf("removed: %v", diff.Removed)
+	}
+	if !reflect.DeepEqual(diff.Changed, []TextCase{"home line"}) {
+		t.Errorf("changed: %v", diff.Changed)
+	}
+	if diff.Renamed["delete bin line"] != "delete bin 2 line" {
+		t.Errorf("renamed: %v", diff.Renamed)
+	}
+}
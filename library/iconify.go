@@ -0,0 +1,365 @@
+package library
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// the dimensions an iconify collection falls back to when they are not given
+const ICONIFY_DEFAULT_SIZE = 16
+
+type IconifyIcon struct {
+	Body   string  `json:"body,omitempty"`
+	Left   float64 `json:"left,omitempty"`
+	Top    float64 `json:"top,omitempty"`
+	Width  float64 `json:"width,omitempty"`
+	Height float64 `json:"height,omitempty"`
+	// in quarter turns
+	Rotate int  `json:"rotate,omitempty"`
+	HFlip  bool `json:"hFlip,omitempty"`
+	VFlip  bool `json:"vFlip,omitempty"`
+}
+
+type IconifyAlias struct {
+	Parent string `json:"parent"`
+	IconifyIcon
+}
+
+// the iconify json collection format, see https://iconify.design/docs/types/iconify-json.html
+type IconifyCollection struct {
+	Prefix     string                  `json:"prefix"`
+	Icons      map[string]IconifyIcon  `json:"icons"`
+	Aliases    map[string]IconifyAlias `json:"aliases,omitempty"`
+	Left       float64                 `json:"left,omitempty"`
+	Top        float64                 `json:"top,omitempty"`
+	Width      float64                 `json:"width,omitempty"`
+	Height     float64                 `json:"height,omitempty"`
+	Categories map[string][]string     `json:"categories,omitempty"`
+}
+
+func ReadIconify(data []byte) (IconifyCollection, error) {
+	collection := IconifyCollection{}
+	err := json.Unmarshal(data, &collection)
+	if err != nil {
+		return collection, err
+	}
+	if collection.Icons == nil {
+		return collection, fmt.Errorf("%s is not an iconify collection, it has no icons", collection.Prefix)
+	}
+	return collection, nil
+}
+
+// fills in the properties an icon inherits from its collection
+func (c IconifyCollection) resolve(icon IconifyIcon) IconifyIcon {
+	if icon.Width == 0 {
+		icon.Width = c.Width
+	}
+	if icon.Height == 0 {
+		icon.Height = c.Height
+	}
+	if icon.Width == 0 {
+		icon.Width = ICONIFY_DEFAULT_SIZE
+	}
+	if icon.Height == 0 {
+		icon.Height = ICONIFY_DEFAULT_SIZE
+	}
+	if icon.Left == 0 {
+		icon.Left = c.Left
+	}
+	if icon.Top == 0 {
+		icon.Top = c.Top
+	}
+	return icon
+}
+
+// looks up an icon or alias, applying the transformations of aliases
+// on top of their parent's
+func (c IconifyCollection) Icon(name string) (IconifyIcon, bool) {
+	return c.icon(name, 0)
+}
+
+func (c IconifyCollection) icon(name string, depth int) (IconifyIcon, bool) {
+	if icon, has := c.Icons[name]; has {
+		return c.resolve(icon), true
+	}
+	// aliases can point to other aliases, the depth keeps loops from hanging
+	alias, has := c.Aliases[name]
+	if !has || depth > 8 {
+		return IconifyIcon{}, false
+	}
+	parent, has := c.icon(alias.Parent, depth+1)
+	if !has {
+		return IconifyIcon{}, false
+	}
+	if alias.Width != 0 {
+		parent.Width = alias.Width
+	}
+	if alias.Height != 0 {
+		parent.Height = alias.Height
+	}
+	parent.Rotate = (parent.Rotate + alias.Rotate) % 4
+	parent.HFlip = parent.HFlip != alias.HFlip
+	parent.VFlip = parent.VFlip != alias.VFlip
+	return parent, true
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// draws the icon the way iconify's iconToSVG does, the flips are applied
+// first and the rotation around them, which turns the box for odd turns
+func (icon IconifyIcon) SVG() []byte {
+	left, top, width, height := icon.Left, icon.Top, icon.Width, icon.Height
+	rotate := icon.Rotate
+
+	var transforms []string
+	switch {
+	// flipping both ways is half a turn
+	case icon.HFlip && icon.VFlip:
+		rotate += 2
+	case icon.HFlip:
+		transforms = append(transforms, fmt.Sprintf(
+			"translate(%s %s) scale(-1 1)",
+			formatNumber(width+left), formatNumber(0-top),
+		))
+		left, top = 0, 0
+	case icon.VFlip:
+		transforms = append(transforms, fmt.Sprintf(
+			"translate(%s %s) scale(1 -1)",
+			formatNumber(0-left), formatNumber(height+top),
+		))
+		left, top = 0, 0
+	}
+
+	rotation := ""
+	switch (rotate%4 + 4) % 4 {
+	case 1:
+		center := formatNumber(height/2 + top)
+		rotation = fmt.Sprintf("rotate(90 %s %s)", center, center)
+	case 2:
+		rotation = fmt.Sprintf("rotate(180 %s %s)", formatNumber(width/2+left), formatNumber(height/2+top))
+	case 3:
+		center := formatNumber(width/2 + left)
+		rotation = fmt.Sprintf("rotate(-90 %s %s)", center, center)
+	}
+	if rotation != "" {
+		transforms = append([]string{rotation}, transforms...)
+	}
+	if rotate%2 != 0 {
+		left, top = top, left
+		width, height = height, width
+	}
+
+	body := icon.Body
+	if len(transforms) > 0 {
+		body = fmt.Sprintf(`<g transform="%s">%s</g>`, strings.Join(transforms, " "), body)
+	}
+	return []byte(fmt.Sprintf(
+		`<svg xmlns="http://www.w3.org/2000/svg" viewBox="%s %s %s %s" width="%s" height="%s">%s</svg>`,
+		formatNumber(left), formatNumber(top),
+		formatNumber(width), formatNumber(height),
+		formatNumber(width), formatNumber(height),
+		body,
+	))
+}
+
+// every icon and alias of the collection as an svg document
+func (c IconifyCollection) SVGs() map[TextCase][]byte {
+	lib := map[TextCase][]byte{}
+	for name := range c.Icons {
+		icon, _ := c.Icon(name)
+		lib[ToTextCase(name)] = icon.SVG()
+	}
+	for name := range c.Aliases {
+		icon, has := c.Icon(name)
+		if has {
+			lib[ToTextCase(name)] = icon.SVG()
+		}
+	}
+	return lib
+}
+
+// the icons of the collection along with their categories
+func (c IconifyCollection) Index() Index {
+	categories := map[string]string{}
+	for category, names := range c.Categories {
+		for _, name := range names {
+			categories[name] = category
+		}
+	}
+
+	index := NewIndex()
+	for name, svg := range c.SVGs() {
+		kebab := strings.ReplaceAll(name, " ", "-")
+		index.AddIcon(name, Icon{
+			Record: Record{
+				Path:     c.Prefix + ":" + kebab,
+				Category: categories[kebab],
+				Style:    StyleFromName(name),
+			},
+			Data: svg,
+		})
+	}
+	return index
+}
+
+type svgDocument struct {
+	ViewBox string `xml:"viewBox,attr"`
+	Width   string `xml:"width,attr"`
+	Height  string `xml:"height,attr"`
+	// the other attributes of the root, like fill and stroke
+	Attrs []xml.Attr `xml:",any,attr"`
+	Inner string     `xml:",innerxml"`
+}
+
+// the presentation attributes of the root as a group around the body,
+// iconify bodies are drawn in an svg that only has the view box
+func (d svgDocument) body() string {
+	body := strings.TrimSpace(d.Inner)
+	attrs := strings.Builder{}
+	for _, a := range d.Attrs {
+		// namespace declarations and namespaced attributes belong to the root
+		if a.Name.Space != "" || a.Name.Local == "xmlns" {
+			continue
+		}
+		attrs.WriteString(" " + a.Name.Local + `="`)
+		xml.EscapeText(&attrs, []byte(a.Value))
+		attrs.WriteString(`"`)
+	}
+	if attrs.Len() == 0 {
+		return body
+	}
+	return "<g" + attrs.String() + ">" + body + "</g>"
+}
+
+// converts an svg document into an iconify icon
+func NewIconifyIcon(svg []byte) (IconifyIcon, error) {
+	document := svgDocument{}
+	err := xml.NewDecoder(bytes.NewReader(svg)).Decode(&document)
+	if err != nil {
+		return IconifyIcon{}, err
+	}
+
+	icon := IconifyIcon{Body: document.body()}
+	box := strings.Fields(strings.ReplaceAll(document.ViewBox, ",", " "))
+	if len(box) == 4 {
+		numbers := make([]float64, 4)
+		for i, b := range box {
+			numbers[i], err = strconv.ParseFloat(b, 64)
+			if err != nil {
+				return IconifyIcon{}, err
+			}
+		}
+		icon.Left, icon.Top, icon.Width, icon.Height = numbers[0], numbers[1], numbers[2], numbers[3]
+		return icon, nil
+	}
+
+	// without a view box the size attributes are the best there is
+	icon.Width, _ = strconv.ParseFloat(strings.TrimSuffix(document.Width, "px"), 64)
+	icon.Height, _ = strconv.ParseFloat(strings.TrimSuffix(document.Height, "px"), 64)
+	return icon, nil
+}
+
+// builds a collection out of svg documents, the most common size becomes
+// the collection's default so that only the odd ones out repeat it,
+// categories are keyed by icon name and may leave icons out
+func NewIconifyCollection(prefix string, icons map[TextCase][]byte, categories map[TextCase]string) (IconifyCollection, error) {
+	collection := IconifyCollection{
+		Prefix: prefix,
+		Icons:  map[string]IconifyIcon{},
+	}
+	for name, category := range categories {
+		if _, has := icons[name]; !has || category == "" {
+			continue
+		}
+		if collection.Categories == nil {
+			collection.Categories = map[string][]string{}
+		}
+		collection.Categories[category] = append(
+			collection.Categories[category], strings.ReplaceAll(name, " ", "-"),
+		)
+	}
+	for _, names := range collection.Categories {
+		sort.Strings(names)
+	}
+
+	converted := map[string]IconifyIcon{}
+	sizes := map[[2]float64]int{}
+	for name, svg := range icons {
+		icon, err := NewIconifyIcon(svg)
+		if err != nil {
+			return collection, fmt.Errorf("%s: %w", name, err)
+		}
+		converted[strings.ReplaceAll(name, " ", "-")] = icon
+		sizes[[2]float64{icon.Width, icon.Height}]++
+	}
+
+	common := [2]float64{ICONIFY_DEFAULT_SIZE, ICONIFY_DEFAULT_SIZE}
+	for size, count := range sizes {
+		if count > sizes[common] || (count == sizes[common] && size[0] > common[0]) {
+			common = size
+		}
+	}
+	collection.Width, collection.Height = common[0], common[1]
+
+	for name, icon := range converted {
+		if icon.Width == collection.Width {
+			icon.Width = 0
+		}
+		if icon.Height == collection.Height {
+			icon.Height = 0
+		}
+		collection.Icons[name] = icon
+	}
+	return collection, nil
+}
+
+// a collection in iconify json format at a url, which may also be a local file
+type Iconify struct {
+	Url    *url.URL
+	Buffer *bytes.Buffer
+	lock   sync.Mutex
+}
+
+func (i *Iconify) Latest(ctx context.Context) (string, error) {
+	defer i.lock.Unlock()
+	i.lock.Lock()
+
+	data, err := Fetch(ctx, i.Url)
+	if err != nil {
+		return "", err
+	}
+	i.Buffer = bytes.NewBuffer(data)
+	return Hash(data), nil
+}
+
+func (i *Iconify) Pull(_ string) (Index, error) {
+	defer i.lock.Unlock()
+	i.lock.Lock()
+
+	var data []byte
+	if i.Buffer != nil {
+		data = i.Buffer.Bytes()
+	} else {
+		var err error
+		data, err = Fetch(context.Background(), i.Url)
+		if err != nil {
+			return Index{}, err
+		}
+	}
+
+	collection, err := ReadIconify(data)
+	if err != nil {
+		return Index{}, err
+	}
+	return collection.Index(), nil
+}
@@ -0,0 +1,102 @@
+package library
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIconify(t *testing.T) {
+	collection, err := ReadIconify([]byte(`{
+		"prefix": "test",
+		"width": 24,
+		"height": 24,
+		"icons": {
+			"arrow-left": {"body": "<path d=\"M1 1\"/>"},
+			"wide": {"body": "<path d=\"M2 2\"/>", "width": 32}
+		},
+		"aliases": {
+			"arrow-right": {"parent": "arrow-left", "hFlip": true},
+			"arrow-right-alt": {"parent": "arrow-right"},
+			"loop": {"parent": "loop"}
+		},
+		"categories": {"Arrows": ["arrow-left", "arrow-right"]}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svgs := collection.SVGs()
+	if len(svgs) != 4 {
+		t.Errorf("expected 4 icons, got %d", len(svgs))
+	}
+	if !strings.Contains(string(svgs["arrow left"]), `viewBox="0 0 24 24"`) {
+		t.Errorf("collection size not inherited: %s", svgs["arrow left"])
+	}
+	if !strings.Contains(string(svgs["arrow right alt"]), "scale(-1 1)") {
+		t.Errorf("alias transformation not applied: %s", svgs["arrow right alt"])
+	}
+
+	exported, err := NewIconifyCollection("test", svgs, map[TextCase]string{
+		"arrow left": "Arrows", "arrow right": "Arrows", "missing": "Arrows",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported.Width != 24 || exported.Height != 24 {
+		t.Errorf("expected the common size to be 24x24, got %vx%v", exported.Width, exported.Height)
+	}
+	if exported.Icons["wide"].Width != 32 || exported.Icons["arrow-left"].Width != 0 {
+		t.Errorf("unexpected icon sizes %v", exported.Icons)
+	}
+	if exported.Icons["arrow-left"].Body != `<path d="M1 1"/>` {
+		t.Errorf("body did not round trip: %s", exported.Icons["arrow-left"].Body)
+	}
+	if arrows := exported.Categories["Arrows"]; len(arrows) != 2 || arrows[0] != "arrow-left" || arrows[1] != "arrow-right" {
+		t.Errorf("expected the arrows to be categorized, got %v", exported.Categories)
+	}
+}
+
+func TestIconifyRootAttributes(t *testing.T) {
+	icon, err := NewIconifyIcon([]byte(
+		`<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" ` +
+			`width="24" height="24" viewBox="0 0 24 24" fill="none" stroke="currentColor" ` +
+			`stroke-width="2" stroke-linecap="round" stroke-linejoin="round"><path d="M5 12h14"/></svg>`,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `<g fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" ` +
+		`stroke-linejoin="round"><path d="M5 12h14"/></g>`
+	if icon.Body != expected {
+		t.Errorf("expected the root attributes to be kept:\ngot:  %s\nwant: %s", icon.Body, expected)
+	}
+}
+
+// the svgs iconify's iconToSVG draws for the aliases in the fixture
+var iconifyTransforms = map[string]string{
+	"arrow up mirrored": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 24" width="16" height="24">` +
+		`<g transform="rotate(90 8 8) translate(24 0) scale(-1 1)"><path d="M1 1"/></g></svg>`,
+	"arrow turned": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 16" width="24" height="16">` +
+		`<g transform="rotate(180 12 8)"><path d="M1 1"/></g></svg>`,
+	"arrow down flipped": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 16 24" width="16" height="24">` +
+		`<g transform="rotate(-90 12 12) translate(0 16) scale(1 -1)"><path d="M1 1"/></g></svg>`,
+}
+
+func TestIconifyTransforms(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "iconify_transforms.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection, err := ReadIconify(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svgs := collection.SVGs()
+	for name, expected := range iconifyTransforms {
+		if got := string(svgs[name]); got != expected {
+			t.Errorf("%s:\ngot:  %s\nwant: %s", name, got, expected)
+		}
+	}
+}
@@ -1,15 +1,8 @@
 package library
 
 import (
-	"archive/tar"
 	"bytes"
-	"compress/gzip"
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
-	"io"
-	"io/ioutil"
-	"log"
 	"net/url"
 	"strings"
 	"sync"
@@ -23,9 +16,23 @@ import (
 )
 
 type Library struct {
-	Index      map[TextCase][]byte
+	// svg data keyed by content hash, shared between versions
+	Blobs map[string][]byte
+	// what each blob looks like, keyed by content hash
+	Fingerprints map[string]Fingerprint
+	// content hashes of every version that has been installed, keyed by tag
+	Manifests map[string]Manifest
+	// metadata of every icon, keyed by version and name
+	Records map[string]map[TextCase]Record
+	// the name collisions found when pulling each version
+	Collisions map[string][]Collision
+	// the version in use
 	Version    string
 	LastUpdate time.Time
+
+	// Deprecated: the single version index of older libraries, it is moved
+	// into Blobs and Manifests by Migrate
+	Index map[TextCase][]byte
 }
 
 // a string with lowercase segments separated by spaces
@@ -35,44 +42,58 @@ func ToTextCase(title string) TextCase {
 	return strings.ReplaceAll(strcase.ToKebab(title), "-", " ")
 }
 
+// the name an icon file is indexed under
+func IconName(path string) TextCase {
+	name := NewPath(path).Basename()
+	name, _ = SplitExtension(name)
+	return ToTextCase(name)
+}
+
 type Provider interface {
-	Latest() (string, error)
-	Pull(string) (map[TextCase][]byte, error)
+	// the context cancels the check, pulling a version isn't cancelled
+	Latest(context.Context) (string, error)
+	Pull(string) (Index, error)
 }
 
+// an archive at a url, which may also be a local file
 type HTTP struct {
-	Url    *url.URL
-	Buffer *bytes.Buffer
-	lock   sync.Mutex
+	Url *url.URL
+	// only read icons inside this directory of the archive
+	Directory string
+	Buffer    *bytes.Buffer
+	lock      sync.Mutex
 }
 
-func (h *HTTP) Latest() (string, error) {
+func (h *HTTP) Latest(ctx context.Context) (string, error) {
 	defer h.lock.Unlock()
 	h.lock.Lock()
 
-	h.Buffer = bytes.NewBuffer(nil)
-	err := requests.
-		URL(h.Url.String()).
-		ToBytesBuffer(h.Buffer).
-		Fetch(context.Background())
+	data, err := Fetch(ctx, h.Url)
 	if err != nil {
 		return "", err
 	}
-	hasher := sha256.New()
-	hasher.Write(h.Buffer.Bytes())
-
-	return hex.EncodeToString(hasher.Sum(nil)), nil
+	h.Buffer = bytes.NewBuffer(data)
+	return Hash(data), nil
 }
 
-func (h *HTTP) Pull(_ string) (map[TextCase][]byte, error) {
-	return GenerateFromTarGz(h.Url)
+func (h *HTTP) Pull(_ string) (Index, error) {
+	defer h.lock.Unlock()
+	h.lock.Lock()
+
+	// the archive has usually just been downloaded to check the version
+	if h.Buffer != nil {
+		return ReadArchiveDir(h.Buffer.Bytes(), h.Directory)
+	}
+	return GenerateFromArchive(h.Url, h.Directory)
 }
 
 type Github struct {
 	RepoPath string
+	// only read icons inside this directory of the repository
+	Directory string
 }
 
-func (g Github) Latest() (string, error) {
+func (g Github) Latest(ctx context.Context) (string, error) {
 	root := &html.Node{}
 
 	builder := requests.
@@ -83,12 +104,12 @@ func (g Github) Latest() (string, error) {
 		}).String()).
 		Handle(requests.ToHTML(root))
 
-	err := builder.Fetch(context.Background())
+	err := builder.Fetch(ctx)
 	if err != nil {
 		return "", err
 	}
 
-	request, err := builder.Request(context.Background())
+	request, err := builder.Request(ctx)
 	if err != nil {
 		return "", err
 	}
@@ -106,62 +127,10 @@ func (g Github) Latest() (string, error) {
 	return splitPath[len(splitPath)-1], nil
 }
 
-func (g Github) Pull(tag string) (map[TextCase][]byte, error) {
-	return GenerateFromTarGz(&url.URL{
+func (g Github) Pull(tag string) (Index, error) {
+	return GenerateFromArchive(&url.URL{
 		Scheme: "https",
 		Host:   "github.com",
 		Path:   NewPath(g.RepoPath, "/archive/refs/tags", tag+".tar.gz").String(),
-	})
-}
-
-func GenerateFromTarGz(loc *url.URL) (map[TextCase][]byte, error) {
-	buffer := bytes.NewBuffer(nil)
-
-	err := requests.
-		URL(loc.String()).
-		ToBytesBuffer(buffer).
-		Fetch(context.Background())
-	if err != nil {
-		return nil, err
-	}
-
-	gzipReader, err := gzip.NewReader(buffer)
-	if err != nil {
-		return nil, err
-	}
-	uncompressed, err := ioutil.ReadAll(gzipReader)
-	if err != nil {
-		return nil, err
-	}
-
-	lib := map[TextCase][]byte{}
-
-	tarReader := tar.NewReader(bytes.NewBuffer(uncompressed))
-	for {
-		header, err := tarReader.Next()
-		if err != nil {
-			break
-		}
-
-		switch header.Typeflag {
-		case tar.TypeReg:
-			buffer := bytes.NewBuffer(nil)
-			_, err := io.Copy(buffer, tarReader)
-			if err != nil {
-				log.Println(err)
-				continue
-			}
-
-			name := NewPath(header.Name).Basename()
-			name, _ = SplitExtension(name)
-			name = ToTextCase(name)
-			if name == "" {
-				break
-			}
-
-			lib[name] = buffer.Bytes()
-		}
-	}
-
-	return lib, nil
+	}, g.Directory)
 }
@@ -1,6 +1,7 @@
 package library
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
@@ -14,7 +15,7 @@ func TestGithub(t *testing.T) {
 	var latest string
 	t.Run("latest", func(t *testing.T) {
 		var err error
-		latest, err = github.Latest()
+		latest, err = github.Latest(context.Background())
 		if err != nil {
 			t.Error(err)
 			return
@@ -29,7 +30,7 @@ func TestGithub(t *testing.T) {
 			return
 		}
 		i := 0
-		for k := range pulled {
+		for k := range pulled.Icons {
 			if i == 10 {
 				break
 			}
@@ -0,0 +1,90 @@
+package library
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// reads icons from the svg files in a directory and its subdirectories
+type Local struct {
+	Root string
+}
+
+func (l Local) files() ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(l.Root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != l.Root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if IsIconFile(path) {
+			files = append(files, path)
+		}
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
+
+// the version of a directory is the hash of the path and content of every icon
+func (l Local) Latest(_ context.Context) (string, error) {
+	files, err := l.files()
+	if err != nil {
+		return "", err
+	}
+	builder := strings.Builder{}
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		relative, err := filepath.Rel(l.Root, path)
+		if err != nil {
+			return "", err
+		}
+		builder.WriteString(filepath.ToSlash(relative))
+		builder.WriteString(Hash(data))
+	}
+	return Hash([]byte(builder.String())), nil
+}
+
+func (l Local) Pull(_ string) (Index, error) {
+	files, err := l.files()
+	if err != nil {
+		return Index{}, err
+	}
+	index := NewIndex()
+	for _, path := range files {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Index{}, err
+		}
+		if IsSVGFont(data) {
+			continue
+		}
+		relative, err := filepath.Rel(l.Root, path)
+		if err != nil {
+			return Index{}, err
+		}
+		// wrapped in the root folder like the contents of archives are
+		index.Add(
+			filepath.Base(filepath.Clean(l.Root))+"/"+filepath.ToSlash(relative),
+			data,
+		)
+	}
+
+	tags, err := os.ReadFile(filepath.Join(l.Root, TAGS_FILE))
+	if err == nil {
+		applyTags(&index, tags)
+	}
+	return index, nil
+}
@@ -0,0 +1,55 @@
+package library
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLocal(t *testing.T) {
+	root := t.TempDir()
+	err := os.MkdirAll(filepath.Join(root, "Arrows"), 0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"Arrows/arrow-left-line.svg": "<svg/>",
+		"homeLine.svg":               "<svg></svg>",
+		"README.md":                  "not an icon",
+	}
+	for path, content := range files {
+		err := os.WriteFile(filepath.Join(root, path), []byte(content), 0666)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	local := Local{Root: root}
+	before, err := local.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pulled, err := local.Pull(before)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pulled.Icons) != 2 || pulled.Icons["home line"].Data == nil {
+		t.Errorf("unexpected icons %v", pulled.Icons)
+	}
+	if pulled.Icons["arrow left line"].Category != "Arrows" {
+		t.Errorf("category was not kept: %v", pulled.Icons["arrow left line"].Record)
+	}
+
+	err = os.WriteFile(filepath.Join(root, "homeLine.svg"), []byte("<svg/>"), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	after, err := local.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before == after {
+		t.Error("changing an icon didn't change the version")
+	}
+}
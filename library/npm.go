@@ -0,0 +1,130 @@
+package library
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"net/url"
+	"strings"
+
+	"github.com/carlmjohnson/requests"
+)
+
+const NPM_REGISTRY = "https://registry.npmjs.org"
+
+// the icons in a package published to an npm registry
+type NPM struct {
+	// defaults to NPM_REGISTRY
+	Registry string
+	Package  string
+	// only read icons inside this directory of the package
+	Directory string
+}
+
+type npmDist struct {
+	Tarball   string `json:"tarball"`
+	Integrity string `json:"integrity"`
+	Shasum    string `json:"shasum"`
+}
+
+type npmPackage struct {
+	DistTags map[string]string `json:"dist-tags"`
+	Versions map[string]struct {
+		Dist npmDist `json:"dist"`
+	} `json:"versions"`
+}
+
+func (n NPM) metadata(ctx context.Context) (npmPackage, error) {
+	registry := n.Registry
+	if registry == "" {
+		registry = NPM_REGISTRY
+	}
+
+	var pkg npmPackage
+	err := requests.
+		URL(strings.TrimSuffix(registry, "/") + "/" + url.PathEscape(n.Package)).
+		Accept("application/json").
+		ToJSON(&pkg).
+		Fetch(ctx)
+	return pkg, err
+}
+
+func (n NPM) Latest(ctx context.Context) (string, error) {
+	pkg, err := n.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+	latest, has := pkg.DistTags["latest"]
+	if !has {
+		return "", fmt.Errorf("%s has no latest dist-tag", n.Package)
+	}
+	return latest, nil
+}
+
+func (n NPM) Pull(version string) (Index, error) {
+	pkg, err := n.metadata(context.Background())
+	if err != nil {
+		return Index{}, err
+	}
+	published, has := pkg.Versions[version]
+	if !has {
+		return Index{}, fmt.Errorf("%s has no version %s", n.Package, version)
+	}
+
+	tarball, err := url.Parse(published.Dist.Tarball)
+	if err != nil {
+		return Index{}, err
+	}
+	data, err := Fetch(context.Background(), tarball)
+	if err != nil {
+		return Index{}, err
+	}
+	err = verifyIntegrity(data, published.Dist)
+	if err != nil {
+		return Index{}, err
+	}
+	return ReadArchiveDir(data, n.Directory)
+}
+
+var integrityHashes = map[string]func() hash.Hash{
+	"sha512": sha512.New,
+	"sha384": sha512.New384,
+	"sha256": sha256.New,
+}
+
+// checks data against the subresource integrity string of a package,
+// falling back to the legacy sha1 shasum for old packages
+func verifyIntegrity(data []byte, dist npmDist) error {
+	checked := false
+	for _, entry := range strings.Fields(dist.Integrity) {
+		algorithm, digest, _ := strings.Cut(entry, "-")
+		newHash, has := integrityHashes[algorithm]
+		if !has {
+			continue
+		}
+		hasher := newHash()
+		hasher.Write(data)
+		if base64.StdEncoding.EncodeToString(hasher.Sum(nil)) == digest {
+			return nil
+		}
+		checked = true
+	}
+	if checked {
+		return fmt.Errorf("integrity check failed for %s", dist.Tarball)
+	}
+
+	if dist.Shasum == "" {
+		return fmt.Errorf("no supported integrity information for %s", dist.Tarball)
+	}
+	hasher := sha1.New()
+	hasher.Write(data)
+	if hex.EncodeToString(hasher.Sum(nil)) != dist.Shasum {
+		return fmt.Errorf("shasum check failed for %s", dist.Tarball)
+	}
+	return nil
+}
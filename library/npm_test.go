@@ -0,0 +1,97 @@
+package library
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func testPackage(t *testing.T) []byte {
+	files := map[string]string{
+		"package/package.json":              "{}",
+		"package/icons/arrow-left-line.svg": "<svg/>",
+	}
+	buffer := bytes.NewBuffer(nil)
+	gzipWriter := gzip.NewWriter(buffer)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		err := writer.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0666,
+			Size:     int64(len(content)),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write([]byte(content))
+	}
+	writer.Close()
+	gzipWriter.Close()
+	return buffer.Bytes()
+}
+
+func TestNPM(t *testing.T) {
+	tarball := testPackage(t)
+	digest := sha512.Sum512(tarball)
+	integrity := "sha512-" + base64.StdEncoding.EncodeToString(digest[:])
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/@icons/set":
+			json.NewEncoder(w).Encode(map[string]any{
+				"dist-tags": map[string]string{"latest": "1.0.0"},
+				"versions": map[string]any{
+					"1.0.0": map[string]any{"dist": map[string]string{
+						"tarball":   server.URL + "/set-1.0.0.tgz",
+						"integrity": integrity,
+					}},
+					"0.9.0": map[string]any{"dist": map[string]string{
+						"tarball":   server.URL + "/set-1.0.0.tgz",
+						"integrity": "sha512-AAAA",
+					}},
+				},
+			})
+		case "/set-1.0.0.tgz":
+			w.Write(tarball)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider := NPM{
+		Registry:  server.URL,
+		Package:   "@icons/set",
+		Directory: "icons",
+	}
+
+	latest, err := provider.Latest(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest != "1.0.0" {
+		t.Errorf("unexpected latest version %s", latest)
+	}
+
+	pulled, err := provider.Pull(latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pulled.Icons) != 1 || string(pulled.Icons["arrow left line"].Data) != "<svg/>" {
+		t.Errorf("unexpected icons %v", pulled.Icons)
+	}
+
+	_, err = provider.Pull("0.9.0")
+	if err == nil {
+		t.Error("expected a tarball with the wrong integrity to be rejected")
+	}
+}
@@ -0,0 +1,216 @@
+package library
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+const (
+	STYLE_LINE = "line"
+	STYLE_FILL = "fill"
+)
+
+// what is known about an icon besides its content
+type Record struct {
+	Hash string
+	// where the icon was found in its source
+	Path     string
+	Category string
+	// STYLE_LINE, STYLE_FILL or empty when the icon has no variants
+	Style string
+	Tags  []string
+}
+
+type Icon struct {
+	Record
+	Data []byte
+}
+
+// a name several different icons in a source normalized to
+type Collision struct {
+	Name TextCase
+	// the paths of the colliding icons, the first one kept the name
+	Paths []string
+	// the names the other icons were stored under instead
+	Renamed []TextCase
+}
+
+// the icons pulled from a provider
+type Index struct {
+	Icons      map[TextCase]Icon
+	Collisions []Collision
+}
+
+func NewIndex() Index {
+	return Index{Icons: map[TextCase]Icon{}}
+}
+
+// the folders icons are commonly kept in, which say nothing about the category
+var containerFolders = map[string]bool{
+	"icons": true,
+	"svg":   true,
+	"svgs":  true,
+}
+
+// the category of an icon is the folder it is in, paths start with
+// the folder the source is wrapped in, like archives usually are
+func CategoryFromPath(path string) string {
+	segments := NewPath(path)
+	if len(segments) < 3 {
+		return ""
+	}
+	folder := segments[len(segments)-2]
+	if containerFolders[strings.ToLower(folder)] {
+		return ""
+	}
+	return folder
+}
+
+func StyleFromName(name TextCase) string {
+	switch {
+	case strings.HasSuffix(name, " "+STYLE_LINE):
+		return STYLE_LINE
+	case strings.HasSuffix(name, " "+STYLE_FILL):
+		return STYLE_FILL
+	}
+	return ""
+}
+
+// the name an icon shares with its other styles, like arrow left for
+// arrow left line and arrow left fill
+func BaseName(name TextCase) TextCase {
+	style := StyleFromName(name)
+	if style == "" {
+		return name
+	}
+	return strings.TrimSuffix(name, " "+style)
+}
+
+// adds an icon file, the name, category and style are derived from its path
+func (i *Index) Add(path string, data []byte) {
+	name := IconName(path)
+	if name == "" {
+		return
+	}
+	i.AddIcon(name, Icon{
+		Record: Record{
+			Path:     path,
+			Category: CategoryFromPath(path),
+			Style:    StyleFromName(name),
+		},
+		Data: data,
+	})
+}
+
+// adds an icon under a name, when another icon already has the name it
+// is stored under its category or a number instead and the collision is kept
+func (i *Index) AddIcon(name TextCase, icon Icon) {
+	icon.Hash = Hash(icon.Data)
+
+	existing, has := i.Icons[name]
+	if !has {
+		i.Icons[name] = icon
+		return
+	}
+	if existing.Hash == icon.Hash {
+		return
+	}
+
+	renamed := name
+	if icon.Category != "" {
+		renamed = ToTextCase(icon.Category) + " " + name
+	}
+	for n := 2; ; n++ {
+		if _, has := i.Icons[renamed]; !has {
+			break
+		}
+		renamed = fmt.Sprintf("%s %d", name, n)
+	}
+	i.Icons[renamed] = icon
+
+	for c := range i.Collisions {
+		if i.Collisions[c].Name == name {
+			i.Collisions[c].Paths = append(i.Collisions[c].Paths, icon.Path)
+			i.Collisions[c].Renamed = append(i.Collisions[c].Renamed, renamed)
+			return
+		}
+	}
+	i.Collisions = append(i.Collisions, Collision{
+		Name:    name,
+		Paths:   []string{existing.Path, icon.Path},
+		Renamed: []TextCase{renamed},
+	})
+}
+
+// the metadata file RemixIcon keeps next to its icons
+const TAGS_FILE = "tags.json"
+
+// whether a path can be an icon, sources also ship stylesheets, fonts
+// and readmes next to them
+func IsIconFile(path string) bool {
+	return strings.HasSuffix(strings.ToLower(path), ".svg")
+}
+
+// icon fonts in svg format hold every icon as a glyph, they are an svg
+// file but not an icon
+func IsSVGFont(data []byte) bool {
+	return bytes.Contains(data, []byte("<font"))
+}
+
+// whether a path is a tags file at the top level of a source
+func IsTagsFile(path string) bool {
+	segments := NewPath(path)
+	return len(segments) <= 2 && len(segments) > 0 &&
+		segments.Basename() == TAGS_FILE
+}
+
+// applies a tags file, which maps categories to icon names to comma
+// separated keywords, icon names in it leave out the style
+func (i *Index) ApplyTags(data []byte) error {
+	file := map[string]json.RawMessage{}
+	err := json.Unmarshal(data, &file)
+	if err != nil {
+		return err
+	}
+
+	type tagged struct {
+		category string
+		tags     []string
+	}
+	byName := map[TextCase]tagged{}
+	for category, raw := range file {
+		names := map[string]string{}
+		// comments and other non category entries are skipped
+		if json.Unmarshal(raw, &names) != nil {
+			continue
+		}
+		for name, keywords := range names {
+			var tags []string
+			for _, k := range strings.Split(keywords, ",") {
+				k = strings.TrimSpace(k)
+				if k != "" {
+					tags = append(tags, k)
+				}
+			}
+			byName[ToTextCase(name)] = tagged{category: category, tags: tags}
+		}
+	}
+
+	for name, icon := range i.Icons {
+		match, has := byName[name]
+		if !has && icon.Style != "" {
+			match, has = byName[strings.TrimSuffix(name, " "+icon.Style)]
+		}
+		if !has {
+			continue
+		}
+		icon.Tags = match.tags
+		if icon.Category == "" {
+			icon.Category = match.category
+		}
+		i.Icons[name] = icon
+	}
+	return nil
+}
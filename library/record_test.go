@@ -0,0 +1,51 @@
+package library
+
+import "testing"
+
+func TestIndexCollisions(t *testing.T) {
+	index := NewIndex()
+	index.Add("RemixIcon/icons/Arrows/arrow-left-line.svg", []byte("a"))
+	index.Add("RemixIcon/icons/System/arrow-left-line.svg", []byte("b"))
+	index.Add("RemixIcon/icons/Other/arrow_left_line.svg", []byte("c"))
+	index.Add("RemixIcon/icons/Copy/arrow-left-line.svg", []byte("a"))
+
+	if len(index.Icons) != 3 {
+		t.Errorf("expected 3 icons, got %v", index.Icons)
+	}
+	if index.Icons["arrow left line"].Category != "Arrows" {
+		t.Errorf("the first icon should keep its name: %v", index.Icons["arrow left line"])
+	}
+	if string(index.Icons["system arrow left line"].Data) != "b" {
+		t.Errorf("colliding icon wasn't renamed by category: %v", index.Icons)
+	}
+	if len(index.Collisions) != 1 || len(index.Collisions[0].Renamed) != 2 {
+		t.Errorf("unexpected collisions %v", index.Collisions)
+	}
+	if index.Icons["arrow left line"].Style != STYLE_LINE {
+		t.Errorf("style wasn't detected: %v", index.Icons["arrow left line"])
+	}
+}
+
+func TestApplyTags(t *testing.T) {
+	index := NewIndex()
+	index.Add("RemixIcon/icons/System/delete-bin-line.svg", []byte("a"))
+	index.Add("RemixIcon/icons/System/delete-bin-fill.svg", []byte("b"))
+	index.Add("RemixIcon/custom.svg", []byte("c"))
+
+	err := index.ApplyTags([]byte(`{
+		"_comment": "not a category",
+		"System": {"delete-bin": "trash, 删除,garbage"},
+		"Misc": {"custom": "mine"}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tags := index.Icons["delete bin fill"].Tags
+	if len(tags) != 3 || tags[0] != "trash" || tags[1] != "删除" {
+		t.Errorf("unexpected tags %v", tags)
+	}
+	if index.Icons["custom"].Category != "Misc" {
+		t.Errorf("category wasn't filled in from the tags file: %v", index.Icons["custom"])
+	}
+}
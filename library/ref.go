@@ -0,0 +1,25 @@
+package library
+
+import "strings"
+
+// a reference to an icon in one of several libraries, written as namespace:name
+type Ref struct {
+	Namespace string
+	Name      TextCase
+}
+
+// parses references like ri:arrow-left-line, the namespace may be omitted
+func ParseRef(ref string) Ref {
+	namespace, name, found := strings.Cut(ref, ":")
+	if !found {
+		return Ref{Name: ToTextCase(ref)}
+	}
+	return Ref{Namespace: namespace, Name: ToTextCase(name)}
+}
+
+func (r Ref) String() string {
+	if r.Namespace == "" {
+		return r.Name
+	}
+	return r.Namespace + ":" + r.Name
+}
@@ -0,0 +1,21 @@
+package library
+
+import "testing"
+
+func TestParseRef(t *testing.T) {
+	cases := map[string]Ref{
+		"ri:arrow-left-line":   {Namespace: "ri", Name: "arrow left line"},
+		"arrow-left-line":      {Name: "arrow left line"},
+		"tabler:ArrowLeft":     {Namespace: "tabler", Name: "arrow left"},
+		"ri:delete bin 2 line": {Namespace: "ri", Name: "delete bin 2 line"},
+	}
+	for input, expected := range cases {
+		ref := ParseRef(input)
+		if ref != expected {
+			t.Errorf("%s: expected %v, got %v", input, expected, ref)
+		}
+		if ParseRef(ref.String()) != ref {
+			t.Errorf("%s: %s doesn't round trip", input, ref)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package library
+
+import (
+	"bytes"
+	"image"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// draws an svg onto a square image of the given size
+func Rasterize(data []byte, size int) (*image.RGBA, error) {
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	icon.SetTarget(0, 0, float64(size), float64(size))
+	rgba := image.NewRGBA(image.Rect(0, 0, size, size))
+	dasher := rasterx.NewDasher(
+		size, size,
+		rasterx.NewScannerGV(size, size, rgba, rgba.Bounds()),
+	)
+	icon.Draw(dasher, 1)
+	return rgba, nil
+}
+
+const (
+	FINGERPRINT_GRID = 8
+	// the size icons are rasterized at before being averaged into the grid
+	FINGERPRINT_RES = 32
+)
+
+// how much of each cell of a grid laid over an icon is covered, icons
+// are drawn in a single color so the coverage is what they look like
+type Fingerprint [FINGERPRINT_GRID * FINGERPRINT_GRID]uint8
+
+func NewFingerprint(data []byte) (Fingerprint, error) {
+	rgba, err := Rasterize(data, FINGERPRINT_RES)
+	if err != nil {
+		return Fingerprint{}, err
+	}
+
+	cell := FINGERPRINT_RES / FINGERPRINT_GRID
+	var fingerprint Fingerprint
+	for y := 0; y < FINGERPRINT_GRID; y++ {
+		for x := 0; x < FINGERPRINT_GRID; x++ {
+			total := 0
+			for py := y * cell; py < (y+1)*cell; py++ {
+				for px := x * cell; px < (x+1)*cell; px++ {
+					total += int(rgba.RGBAAt(px, py).A)
+				}
+			}
+			fingerprint[y*FINGERPRINT_GRID+x] = uint8(total / (cell * cell))
+		}
+	}
+	return fingerprint, nil
+}
+
+// how different two icons look, 0 when they cover the same cells
+func (f Fingerprint) Distance(other Fingerprint) int {
+	distance := 0
+	for i := range f {
+		d := int(f[i]) - int(other[i])
+		if d < 0 {
+			d = -d
+		}
+		distance += d
+	}
+	return distance
+}
+
+// the fingerprint of a stored blob, there is none for blobs that could
+// not be rasterized
+func (l Library) Fingerprint(hash string) (Fingerprint, bool) {
+	fingerprint, has := l.Fingerprints[hash]
+	return fingerprint, has
+}
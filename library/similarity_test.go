@@ -0,0 +1,72 @@
+package library
+
+import "testing"
+
+const (
+	squareSVG        = `<svg viewBox="0 0 24 24"><path d="M4 4h16v16H4z"/></svg>`
+	smallerSquareSVG = `<svg viewBox="0 0 24 24"><path d="M5 5h14v14H5z"/></svg>`
+	barSVG           = `<svg viewBox="0 0 24 24"><path d="M0 0h24v4H0z"/></svg>`
+)
+
+func TestFingerprint(t *testing.T) {
+	fingerprint := func(svg string) Fingerprint {
+		f, err := NewFingerprint([]byte(svg))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	square := fingerprint(squareSVG)
+	smaller := fingerprint(smallerSquareSVG)
+	bar := fingerprint(barSVG)
+
+	if d := square.Distance(fingerprint(squareSVG)); d != 0 {
+		t.Errorf("expected the same icon to have no distance, got %d", d)
+	}
+	if square.Distance(smaller) >= square.Distance(bar) {
+		t.Errorf(
+			"expected squares to look more alike than a square and a bar, got %d and %d",
+			square.Distance(smaller), square.Distance(bar),
+		)
+	}
+	if square.Distance(bar) != bar.Distance(square) {
+		t.Error("distance is not symmetric")
+	}
+}
+
+func TestLibraryFingerprints(t *testing.T) {
+	index := NewIndex()
+	index.AddIcon("square", Icon{Data: []byte(squareSVG)})
+
+	lib := Library{}
+	lib.Add("v1", index)
+	lib.Add("v2", NewIndex())
+	lib.Version = "v2"
+
+	if _, has := lib.Fingerprints[Hash([]byte(squareSVG))]; !has {
+		t.Error("expected a fingerprint to be stored with the icon")
+	}
+	if _, has := lib.Fingerprint("missing"); has {
+		t.Error("expected no fingerprint for an icon that isn't stored")
+	}
+
+	// libraries from before fingerprints were kept have them filled in once
+	delete(lib.Fingerprints, Hash([]byte(squareSVG)))
+	if !lib.Migrate() {
+		t.Error("expected the missing fingerprint to be migrated")
+	}
+	if _, has := lib.Fingerprint(Hash([]byte(squareSVG))); !has {
+		t.Error("expected the fingerprint to be computed from the blob")
+	}
+	if lib.Migrate() {
+		t.Error("expected nothing left to migrate")
+	}
+
+	lib.Fingerprints[Hash([]byte(squareSVG))] = Fingerprint{}
+	if err := lib.Remove("v1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(lib.Fingerprints) != 0 {
+		t.Errorf("expected fingerprints to be collected with their blobs, got %d", len(lib.Fingerprints))
+	}
+}
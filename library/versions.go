@@ -0,0 +1,158 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+)
+
+// moves the index of libraries written before versions were kept
+// side by side into the deduplicated store and fingerprints the blobs
+// stored before fingerprints were kept, returns whether it changed anything
+func (l *Library) Migrate() bool {
+	migrated := false
+	if len(l.Index) > 0 {
+		index := NewIndex()
+		for name, data := range l.Index {
+			index.AddIcon(name, Icon{Data: data})
+		}
+		l.Add(l.Version, index)
+		l.Index = nil
+		migrated = true
+	}
+
+	if l.Fingerprints == nil {
+		l.Fingerprints = map[string]Fingerprint{}
+	}
+	for hash, data := range l.Blobs {
+		if _, has := l.Fingerprints[hash]; has {
+			continue
+		}
+		fingerprint, err := NewFingerprint(data)
+		if err == nil {
+			l.Fingerprints[hash] = fingerprint
+			migrated = true
+		}
+	}
+	return migrated
+}
+
+// stores an index under the given version and returns its manifest
+func (l *Library) Add(version string, index Index) Manifest {
+	if l.Blobs == nil {
+		l.Blobs = map[string][]byte{}
+	}
+	if l.Manifests == nil {
+		l.Manifests = map[string]Manifest{}
+	}
+	if l.Fingerprints == nil {
+		l.Fingerprints = map[string]Fingerprint{}
+	}
+	if l.Records == nil {
+		l.Records = map[string]map[TextCase]Record{}
+	}
+	if l.Collisions == nil {
+		l.Collisions = map[string][]Collision{}
+	}
+
+	manifest := Manifest{}
+	records := map[TextCase]Record{}
+	for name, icon := range index.Icons {
+		hash := Hash(icon.Data)
+		icon.Record.Hash = hash
+		manifest[name] = hash
+		records[name] = icon.Record
+		l.Blobs[hash] = icon.Data
+		if _, has := l.Fingerprints[hash]; !has {
+			fingerprint, err := NewFingerprint(icon.Data)
+			if err == nil {
+				l.Fingerprints[hash] = fingerprint
+			}
+		}
+	}
+	l.Manifests[version] = manifest
+	l.Records[version] = records
+	l.Collisions[version] = index.Collisions
+	return manifest
+}
+
+// removes a version and every blob no other version refers to
+func (l *Library) Remove(version string) error {
+	if version == l.Version {
+		return fmt.Errorf("cannot remove %s, it is the version in use", version)
+	}
+	if _, has := l.Manifests[version]; !has {
+		return fmt.Errorf("version %s is not installed", version)
+	}
+	delete(l.Manifests, version)
+	delete(l.Records, version)
+	delete(l.Collisions, version)
+
+	referenced := map[string]bool{}
+	for _, manifest := range l.Manifests {
+		for _, hash := range manifest {
+			referenced[hash] = true
+		}
+	}
+	for hash := range l.Blobs {
+		if !referenced[hash] {
+			delete(l.Blobs, hash)
+			delete(l.Fingerprints, hash)
+		}
+	}
+	return nil
+}
+
+// a version is installed when the content of all its icons is stored,
+// versions recorded only for migrations have just their manifest
+func (l Library) Installed(version string) bool {
+	manifest, has := l.Manifests[version]
+	if !has {
+		return false
+	}
+	for _, hash := range manifest {
+		if _, has := l.Blobs[hash]; !has {
+			return false
+		}
+	}
+	return true
+}
+
+func (l Library) Versions() []string {
+	versions := make([]string, 0, len(l.Manifests))
+	for v := range l.Manifests {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+	return versions
+}
+
+func (l Library) Icon(version string, name TextCase) ([]byte, bool) {
+	hash, has := l.Manifests[version][name]
+	if !has {
+		return nil, false
+	}
+	data, has := l.Blobs[hash]
+	return data, has
+}
+
+func (l Library) Icons(version string) map[TextCase][]byte {
+	index := map[TextCase][]byte{}
+	for name, hash := range l.Manifests[version] {
+		data, has := l.Blobs[hash]
+		if has {
+			index[name] = data
+		}
+	}
+	return index
+}
+
+// the metadata of an icon, versions installed before metadata was kept
+// only know the hash
+func (l Library) Record(version string, name TextCase) (Record, bool) {
+	record, has := l.Records[version][name]
+	if has {
+		return record, true
+	}
+	hash, has := l.Manifests[version][name]
+	return Record{Hash: hash, Style: StyleFromName(name)}, has
+}
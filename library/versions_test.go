@@ -0,0 +1,40 @@
+package library
+
+import "testing"
+
+func TestVersions(t *testing.T) {
+	index := func(icons map[TextCase]string) Index {
+		index := NewIndex()
+		for name, data := range icons {
+			index.AddIcon(name, Icon{Data: []byte(data)})
+		}
+		return index
+	}
+
+	lib := Library{}
+	lib.Add("v1", index(map[TextCase]string{
+		"home line": "a",
+		"user line": "b",
+	}))
+	lib.Add("v2", index(map[TextCase]string{
+		"home line": "a",
+		"user line": "c",
+	}))
+	lib.Version = "v2"
+
+	if len(lib.Blobs) != 3 {
+		t.Errorf("expected shared content to be stored once, got %d blobs", len(lib.Blobs))
+	}
+	if err := lib.Remove("v2"); err == nil {
+		t.Error("removed the version in use")
+	}
+	if err := lib.Remove("v1"); err != nil {
+		t.Error(err)
+	}
+	if len(lib.Blobs) != 2 || !lib.Installed("v2") {
+		t.Errorf("unreferenced blobs were not collected: %v", lib.Blobs)
+	}
+	if data, _ := lib.Icon("v2", "user line"); string(data) != "c" {
+		t.Errorf("unexpected icon data %q", data)
+	}
+}
@@ -0,0 +1,45 @@
+package search
+
+// where entries without a category are counted
+const UNCATEGORIZED = "uncategorized"
+
+// the node of the category tree an entry is in, written as namespace/category
+func CategoryKey(e Entry) string {
+	category := e.Record.Category
+	if category == "" {
+		category = UNCATEGORIZED
+	}
+	return e.Ref.Namespace + "/" + category
+}
+
+// whether an entry is under a node of the category tree, which is either
+// a namespace, a category or empty for every entry
+func InCategory(e Entry, key string) bool {
+	return key == "" || key == e.Ref.Namespace || key == CategoryKey(e)
+}
+
+func FilterCategory(entries []Entry, key string) []Entry {
+	if key == "" {
+		return entries
+	}
+	var filtered []Entry
+	for _, e := range entries {
+		if InCategory(e, key) {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+// the number of groups under every node of the category tree, a group is
+// counted where its first variant is
+func CountCategories(groups []Group) map[string]int {
+	counts := map[string]int{}
+	for _, g := range groups {
+		first := g.Variants[0]
+		counts[""]++
+		counts[first.Ref.Namespace]++
+		counts[CategoryKey(first)]++
+	}
+	return counts
+}
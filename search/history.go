@@ -0,0 +1,97 @@
+package search
+
+import "icon-cli/library"
+
+// how many exported icons are remembered as recent
+const RECENT_LIMIT = 50
+
+// the icons someone starred and exported, references are written as
+// namespace:name
+type History struct {
+	// in the order they were starred
+	Favorites []string
+	// the most recently exported first
+	Recent []string
+	// how often each icon was exported, fed to the Scorer
+	Usage map[string]int
+}
+
+func NewHistory() History {
+	return History{Usage: map[string]int{}}
+}
+
+func (h History) IsFavorite(ref library.Ref) bool {
+	for _, f := range h.Favorites {
+		if f == ref.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// stars an icon or unstars it when it already is, returns whether it is
+// starred afterwards
+func (h *History) ToggleFavorite(ref library.Ref) bool {
+	for i, f := range h.Favorites {
+		if f == ref.String() {
+			h.Favorites = append(h.Favorites[:i], h.Favorites[i+1:]...)
+			return false
+		}
+	}
+	h.Favorites = append(h.Favorites, ref.String())
+	return true
+}
+
+// counts the icons as used and moves them to the front of the recent ones
+func (h *History) Use(refs ...library.Ref) {
+	if h.Usage == nil {
+		h.Usage = map[string]int{}
+	}
+	for _, ref := range refs {
+		id := ref.String()
+		h.Usage[id]++
+		recent := []string{id}
+		for _, r := range h.Recent {
+			if r != id {
+				recent = append(recent, r)
+			}
+		}
+		if len(recent) > RECENT_LIMIT {
+			recent = recent[:RECENT_LIMIT]
+		}
+		h.Recent = recent
+	}
+}
+
+// moves the groups with a starred variant to the front, then the ones with
+// a recently used variant, the counts say how many of each were moved
+func (h History) Pin(groups []Group) (pinned []Group, favorites, recent int) {
+	at := map[string]int{}
+	for i, g := range groups {
+		for _, v := range g.Variants {
+			at[v.Ref.String()] = i
+		}
+	}
+	taken := map[int]bool{}
+	take := func(ids []string) int {
+		count := 0
+		for _, id := range ids {
+			i, has := at[id]
+			if !has || taken[i] {
+				continue
+			}
+			taken[i] = true
+			pinned = append(pinned, groups[i])
+			count++
+		}
+		return count
+	}
+	favorites = take(h.Favorites)
+	recent = take(h.Recent)
+	for i, g := range groups {
+		if !taken[i] {
+			pinned = append(pinned, g)
+		}
+	}
+	return pinned, favorites, recent
+}
@@ -0,0 +1,244 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// a part of a query that entries have to satisfy
+type Node interface {
+	Match(Entry) bool
+}
+
+// matches when all of its nodes match
+type And []Node
+
+func (a And) Match(e Entry) bool {
+	for _, n := range a {
+		if !n.Match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+type Not struct {
+	Node Node
+}
+
+func (n Not) Match(e Entry) bool {
+	return !n.Node.Match(e)
+}
+
+// free text, which matches anywhere in the name or tags
+type Text struct {
+	Value string
+	// quoted text has to appear as is, other text is only used for ranking
+	Phrase bool
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func (t Text) Match(e Entry) bool {
+	if containsFold(e.Ref.Name, t.Value) {
+		return true
+	}
+	for _, tag := range e.Record.Tags {
+		if containsFold(tag, t.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	FIELD_NAME     = "name"
+	FIELD_STYLE    = "style"
+	FIELD_CATEGORY = "category"
+	FIELD_TAG      = "tag"
+	FIELD_LIBRARY  = "lib"
+	FIELD_VERSION  = "version"
+)
+
+// other ways of writing the names of fields
+var fieldAliases = map[string]string{
+	FIELD_NAME:     FIELD_NAME,
+	FIELD_STYLE:    FIELD_STYLE,
+	FIELD_CATEGORY: FIELD_CATEGORY,
+	"cat":          FIELD_CATEGORY,
+	FIELD_TAG:      FIELD_TAG,
+	FIELD_LIBRARY:  FIELD_LIBRARY,
+	"library":      FIELD_LIBRARY,
+	"ns":           FIELD_LIBRARY,
+	FIELD_VERSION:  FIELD_VERSION,
+}
+
+// a qualifier like style:fill, the operator is only used by versions
+type Field struct {
+	Name     string
+	Operator string
+	Value    string
+}
+
+func (f Field) Match(e Entry) bool {
+	switch f.Name {
+	case FIELD_NAME:
+		return containsFold(e.Ref.Name, f.Value)
+	case FIELD_STYLE:
+		return strings.EqualFold(e.Record.Style, f.Value)
+	case FIELD_CATEGORY:
+		return containsFold(e.Record.Category, f.Value)
+	case FIELD_TAG:
+		for _, tag := range e.Record.Tags {
+			if strings.EqualFold(tag, f.Value) {
+				return true
+			}
+		}
+		return false
+	case FIELD_LIBRARY:
+		return strings.EqualFold(e.Ref.Namespace, f.Value)
+	case FIELD_VERSION:
+		comparison := CompareVersions(e.Version, f.Value)
+		switch f.Operator {
+		case ">=":
+			return comparison >= 0
+		case "<=":
+			return comparison <= 0
+		case ">":
+			return comparison > 0
+		case "<":
+			return comparison < 0
+		case "!=":
+			return comparison != 0
+		}
+		return comparison == 0
+	}
+	return false
+}
+
+// compares version tags like v3.5.0 by their numeric parts, -1 when a
+// is older, 1 when it is newer and 0 when they are the same
+func CompareVersions(a, b string) int {
+	parse := func(v string) []int {
+		var parts []int
+		for _, p := range strings.FieldsFunc(v, func(r rune) bool {
+			return !unicode.IsDigit(r)
+		}) {
+			n, _ := strconv.Atoi(p)
+			parts = append(parts, n)
+		}
+		return parts
+	}
+	left, right := parse(a), parse(b)
+	for i := 0; i < len(left) || i < len(right); i++ {
+		var l, r int
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		if l < r {
+			return -1
+		}
+		if l > r {
+			return 1
+		}
+	}
+	return 0
+}
+
+type Query struct {
+	// every entry in the results has to match the filter
+	Filter And
+	// the free text the results are ranked on
+	Text string
+}
+
+// splits a query into words, keeping quoted phrases together
+// and marking them with the returned flags
+func tokenize(query string) ([]string, []bool) {
+	var tokens []string
+	var quoted []bool
+
+	current := strings.Builder{}
+	inQuotes := false
+	wasQuoted := false
+	flush := func() {
+		if current.Len() > 0 || wasQuoted {
+			tokens = append(tokens, current.String())
+			quoted = append(quoted, wasQuoted)
+		}
+		current.Reset()
+		wasQuoted = false
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			wasQuoted = true
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens, quoted
+}
+
+// parses queries like `arrow style:fill -circle "left line" version:>=3.0`
+func Parse(query string) Query {
+	result := Query{}
+	var text []string
+
+	tokens, quoted := tokenize(query)
+	for i, token := range tokens {
+		negated := false
+		if strings.HasPrefix(token, "-") && len(token) > 1 {
+			negated = true
+			token = token[1:]
+		}
+
+		var node Node
+		key, value, found := strings.Cut(token, ":")
+		name, isField := fieldAliases[strings.ToLower(key)]
+		switch {
+		case found && isField:
+			field := Field{Name: name, Value: value}
+			if name == FIELD_VERSION {
+				for _, op := range []string{">=", "<=", "!=", ">", "<", "="} {
+					if strings.HasPrefix(value, op) {
+						field.Operator = op
+						field.Value = value[len(op):]
+						break
+					}
+				}
+			}
+			node = field
+		case quoted[i]:
+			node = Text{Value: token, Phrase: true}
+		default:
+			// unqualified words only rank results, unless they exclude them
+			if !negated {
+				text = append(text, token)
+				continue
+			}
+			node = Text{Value: token}
+		}
+
+		if negated {
+			node = Not{Node: node}
+		} else if phrase, ok := node.(Text); ok {
+			text = append(text, phrase.Value)
+		}
+		result.Filter = append(result.Filter, node)
+	}
+
+	result.Text = strings.Join(text, " ")
+	return result
+}
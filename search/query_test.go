@@ -0,0 +1,68 @@
+package search
+
+import (
+	"icon-cli/library"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	query := Parse(`arrow style:fill -circle "left line" cat:"Document Editing" version:>=3.0`)
+	expected := Query{
+		Filter: And{
+			Field{Name: FIELD_STYLE, Value: "fill"},
+			Not{Node: Text{Value: "circle"}},
+			Text{Value: "left line", Phrase: true},
+			Field{Name: FIELD_CATEGORY, Value: "Document Editing"},
+			Field{Name: FIELD_VERSION, Operator: ">=", Value: "3.0"},
+		},
+		Text: "arrow left line",
+	}
+	if !reflect.DeepEqual(query, expected) {
+		t.Errorf("expected %#v, got %#v", expected, query)
+	}
+
+	// namespaced references aren't qualifiers
+	if query := Parse("ri:arrow"); len(query.Filter) != 0 || query.Text != "ri:arrow" {
+		t.Errorf("unexpected query %#v", query)
+	}
+}
+
+func TestSearchQuery(t *testing.T) {
+	entries := []Entry{
+		{
+			Ref:     library.Ref{Namespace: "ri", Name: "arrow left line"},
+			Record:  library.Record{Category: "Arrows", Style: library.STYLE_LINE},
+			Version: "v3.5.0",
+		},
+		{
+			Ref:     library.Ref{Namespace: "ri", Name: "arrow left fill"},
+			Record:  library.Record{Category: "Arrows", Style: library.STYLE_FILL},
+			Version: "v3.5.0",
+		},
+		{
+			Ref:     library.Ref{Namespace: "ri", Name: "arrow left circle fill"},
+			Record:  library.Record{Category: "Arrows", Style: library.STYLE_FILL},
+			Version: "v3.5.0",
+		},
+		{
+			Ref:     library.Ref{Namespace: "old", Name: "arrow left fill"},
+			Record:  library.Record{Style: library.STYLE_FILL},
+			Version: "v2.5.0",
+		},
+	}
+
+	cases := map[string][]string{
+		"arrow style:fill -circle version:>=3.0": {"ri:arrow left fill"},
+		"arrow style:fill lib:old":               {"old:arrow left fill"},
+		`"left circle"`:                          {"ri:arrow left circle fill"},
+		`"circle left"`:                          nil,
+		"category:arrows style:line":             {"ri:arrow left line"},
+	}
+	for query, expected := range cases {
+		got := names(Search(query, entries))
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: expected %v, got %v", query, expected, got)
+		}
+	}
+}
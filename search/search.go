@@ -0,0 +1,169 @@
+package search
+
+import (
+	"icon-cli/library"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/lithammer/fuzzysearch/fuzzy"
+)
+
+// an icon as search sees it
+type Entry struct {
+	Ref    library.Ref
+	Record library.Record
+	// the version of the library the icon is from
+	Version string
+}
+
+type Result struct {
+	Entry
+	// how well the entry matches, higher is better
+	Score float64
+	// the levenshtein distance between the query and the name, which
+	// breaks ties between equal scores
+	Distance int
+}
+
+// the points each kind of match of a query word is worth
+const (
+	SCORE_WORD          = 10
+	SCORE_PREFIX        = 6
+	SCORE_FIRST_WORD    = 2
+	SCORE_SUBSTRING     = 3
+	SCORE_FUZZY         = 1
+	SCORE_TAG           = 5
+	SCORE_TAG_PREFIX    = 3
+	SCORE_CATEGORY      = 2
+	SCORE_USAGE         = 2
+	PENALTY_EXTRA_WORDS = 0.1
+)
+
+type Scorer struct {
+	// how often each icon has been used, keyed by reference
+	Usage map[string]int
+}
+
+// the best score of a query word against the words of a name
+func scoreName(word string, name []string) float64 {
+	best := 0.0
+	for i, n := range name {
+		score := 0.0
+		switch {
+		case n == word:
+			score = SCORE_WORD
+		case strings.HasPrefix(n, word):
+			score = SCORE_PREFIX
+		case strings.Contains(n, word):
+			score = SCORE_SUBSTRING
+		}
+		if score > 0 && i == 0 {
+			score += SCORE_FIRST_WORD
+		}
+		best = math.Max(best, score)
+	}
+	return best
+}
+
+func scoreTags(word string, tags []string) float64 {
+	best := 0.0
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		switch {
+		case tag == word:
+			return SCORE_TAG
+		case strings.HasPrefix(tag, word):
+			best = SCORE_TAG_PREFIX
+		}
+	}
+	return best
+}
+
+// scores an entry against free text, every word of the text has to
+// match the name, tags or category somewhere for the entry to match
+func (s Scorer) Score(text string, e Entry) (float64, bool) {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return 0, true
+	}
+	name := strings.Fields(e.Ref.Name)
+	category := strings.ToLower(e.Record.Category)
+
+	total := 0.0
+	for _, word := range words {
+		score := math.Max(scoreName(word, name), scoreTags(word, e.Record.Tags))
+		if score == 0 && category != "" && strings.Contains(category, word) {
+			score = SCORE_CATEGORY
+		}
+		// words typed without spaces, like arrowleft, still match
+		if score == 0 && fuzzy.MatchFold(word, e.Ref.Name) {
+			score = SCORE_FUZZY
+		}
+		if score == 0 {
+			return 0, false
+		}
+		total += score
+	}
+
+	if extra := len(name) - len(words); extra > 0 {
+		total -= PENALTY_EXTRA_WORDS * float64(extra)
+	}
+	if used := s.Usage[e.Ref.String()]; used > 0 {
+		total += SCORE_USAGE * math.Log2(1+float64(used))
+	}
+	return total, true
+}
+
+// filters entries with the qualifiers of the query and ranks the rest
+func (s Scorer) Rank(query Query, entries []Entry) []Result {
+	text := strings.ToLower(query.Text)
+
+	var results []Result
+	for _, e := range entries {
+		if !query.Filter.Match(e) {
+			continue
+		}
+		score, matched := s.Score(text, e)
+		if !matched {
+			continue
+		}
+		results = append(results, Result{
+			Entry:    e,
+			Score:    score,
+			Distance: fuzzy.LevenshteinDistance(text, e.Ref.Name),
+		})
+	}
+
+	// results are large, so their order is sorted rather than themselves
+	order := make([]int, len(results))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool {
+		i, j := &results[order[a]], &results[order[b]]
+		if i.Score != j.Score {
+			return i.Score > j.Score
+		}
+		if i.Distance != j.Distance {
+			return i.Distance < j.Distance
+		}
+		if i.Ref.Namespace != j.Ref.Namespace {
+			return i.Ref.Namespace < j.Ref.Namespace
+		}
+		return i.Ref.Name < j.Ref.Name
+	})
+
+	sorted := make([]Result, len(results))
+	for i, o := range order {
+		sorted[i] = results[o]
+	}
+	return sorted
+}
+
+// filters entries with the qualifiers of the query and ranks the rest on
+// their name, tags and category, entries that match none of them are
+// left out, see Parse for the syntax
+func Search(query string, entries []Entry) []Result {
+	return Scorer{}.Rank(Parse(query), entries)
+}
@@ -0,0 +1,236 @@
+package search
+
+import (
+	"icon-cli/common"
+	"icon-cli/library"
+	"os"
+	"strings"
+	"testing"
+)
+
+var testEntries = []Entry{
+	{
+		Ref:    library.Ref{Namespace: "ri", Name: "delete bin line"},
+		Record: library.Record{Category: "System", Tags: []string{"trash", "garbage"}},
+	},
+	{
+		Ref:    library.Ref{Namespace: "ri", Name: "trash can"},
+		Record: library.Record{Category: "System"},
+	},
+	{
+		Ref:    library.Ref{Namespace: "ri", Name: "home line"},
+		Record: library.Record{Category: "Buildings"},
+	},
+	{
+		Ref:    library.Ref{Namespace: "ri", Name: "home 2 line"},
+		Record: library.Record{Category: "Buildings"},
+	},
+	{
+		Ref:    library.Ref{Namespace: "ri", Name: "arrow left line"},
+		Record: library.Record{Category: "Arrows"},
+	},
+	{
+		Ref:    library.Ref{Namespace: "ri", Name: "sort arrow left line"},
+		Record: library.Record{Category: "Arrows"},
+	},
+}
+
+func names(results []Result) []string {
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Ref.String())
+	}
+	return names
+}
+
+func TestSearch(t *testing.T) {
+	results := Search("trash", testEntries)
+	if len(results) != 2 || results[0].Ref.Name != "trash can" || results[1].Ref.Name != "delete bin line" {
+		t.Errorf("expected names to rank above tags: %v", names(results))
+	}
+
+	results = Search("buildings", testEntries)
+	if len(results) != 2 {
+		t.Errorf("category search failed: %v", names(results))
+	}
+
+	results = Search("arrow left", testEntries)
+	if len(results) != 2 || results[0].Ref.Name != "arrow left line" {
+		t.Errorf("expected the name starting with the query first: %v", names(results))
+	}
+
+	results = Search("hom", testEntries)
+	if len(results) != 2 || results[0].Ref.Name != "home line" {
+		t.Errorf("expected the shorter name first: %v", names(results))
+	}
+
+	results = Search("arrowleft", testEntries)
+	if len(results) != 2 {
+		t.Errorf("expected queries without spaces to match: %v", names(results))
+	}
+
+	if results := Search("nothing like it", testEntries); len(results) != 0 {
+		t.Errorf("expected no results, got %v", names(results))
+	}
+}
+
+func TestUsage(t *testing.T) {
+	scorer := Scorer{Usage: map[string]int{"ri:home 2 line": 8}}
+	results := scorer.Rank(Parse("home"), testEntries)
+	if len(results) != 2 || results[0].Ref.Name != "home 2 line" {
+		t.Errorf("expected frequently used icons first: %v", names(results))
+	}
+}
+
+// benchmarks run over the installed library at $ICON_LIBRARY (an icons.*.bin
+// store), or a generated index about the size of RemixIcon without one
+func benchmarkEntries(b *testing.B) []Entry {
+	path := os.Getenv("ICON_LIBRARY")
+	if path == "" {
+		return generatedEntries()
+	}
+
+	store := common.NewStore(path, library.Library{})
+	err := store.Load()
+	if err != nil {
+		b.Fatal(err)
+	}
+	store.Data.Migrate()
+
+	var entries []Entry
+	for name := range store.Data.Manifests[store.Data.Version] {
+		record, _ := store.Data.Record(store.Data.Version, name)
+		entries = append(entries, Entry{
+			Ref:     library.Ref{Namespace: "ri", Name: name},
+			Record:  record,
+			Version: store.Data.Version,
+		})
+	}
+	if len(entries) == 0 {
+		b.Fatalf("%s has no icons installed", path)
+	}
+	return entries
+}
+
+func generatedEntries() []Entry {
+	subjects := []string{
+		"arrow", "home", "user", "file", "folder", "delete bin", "mail", "chat",
+		"settings", "search", "star", "heart", "camera", "image", "music",
+		"map pin", "phone", "lock", "cloud", "calendar", "bookmark", "bell",
+		"edit", "share", "download", "upload", "wifi", "battery", "shield",
+		"flag", "gift", "shopping cart", "bank card", "code", "terminal",
+		"database", "server", "bug", "book", "pencil",
+	}
+	modifiers := []string{
+		"", "2", "3", "4", "add", "check", "close", "forbid", "info", "lock",
+		"search", "settings", "shared", "star", "up", "down", "left", "right",
+		"circle", "warning", "transfer", "unlock", "zip", "history", "list",
+		"line", "open", "reduce", "smile", "voice", "follow", "shield", "hide",
+		"received",
+	}
+	categories := []string{"System", "Arrows", "Document", "Media", "Business"}
+
+	var entries []Entry
+	for i, subject := range subjects {
+		for _, modifier := range modifiers {
+			for _, style := range []string{library.STYLE_LINE, library.STYLE_FILL} {
+				name := strings.Join(strings.Fields(subject+" "+modifier+" "+style), " ")
+				entries = append(entries, Entry{
+					Ref: library.Ref{Namespace: "ri", Name: name},
+					Record: library.Record{
+						Category: categories[i%len(categories)],
+						Style:    style,
+						Tags:     []string{subject, modifier},
+					},
+					Version: "v3.5.0",
+				})
+			}
+		}
+	}
+	return entries
+}
+
+func BenchmarkSearch(b *testing.B) {
+	entries := benchmarkEntries(b)
+	queries := []string{
+		"a", "arrow", "arrow left", "trash", "delete bin line",
+		"arrowleft", "user style:fill -circle", "category:system settings",
+	}
+	for _, query := range queries {
+		b.Run(query, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Search(query, entries)
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Parse(`arrow style:fill -circle "left line" version:>=3.0`)
+	}
+}
+
+func TestGroupVariants(t *testing.T) {
+	entries := []Entry{
+		{Ref: library.Ref{Namespace: "ri", Name: "arrow left fill"}},
+		{Ref: library.Ref{Namespace: "ri", Name: "home line"}},
+		{Ref: library.Ref{Namespace: "ri", Name: "arrow left line"}},
+		{Ref: library.Ref{Namespace: "mdi", Name: "arrow left"}},
+	}
+	groups := GroupVariants(entries)
+	if len(groups) != 3 {
+		t.Fatalf("expected 3 groups, got %v", groups)
+	}
+	if groups[0].Ref.String() != "ri:arrow left" || len(groups[0].Variants) != 2 {
+		t.Errorf("expected the arrow variants grouped first: %v", groups[0])
+	}
+	if styles := groups[0].Styles(); styles[0] != "fill" || styles[1] != "line" {
+		t.Errorf("expected the variants in entry order: %v", styles)
+	}
+	if groups[2].Ref.String() != "mdi:arrow left" || len(groups[2].Variants) != 1 {
+		t.Errorf("expected other namespaces kept apart: %v", groups[2])
+	}
+}
+
+func TestCategories(t *testing.T) {
+	entries := append(testEntries, Entry{Ref: library.Ref{Namespace: "mdi", Name: "home"}})
+	counts := CountCategories(GroupVariants(entries))
+	if counts[""] != 7 || counts["ri"] != 6 || counts["ri/Buildings"] != 2 || counts["mdi/"+UNCATEGORIZED] != 1 {
+		t.Errorf("unexpected counts %v", counts)
+	}
+
+	arrows := FilterCategory(entries, "ri/Arrows")
+	if len(arrows) != 2 || FilterCategory(entries, "mdi")[0].Ref.Name != "home" {
+		t.Errorf("expected filtering by category and namespace: %v", arrows)
+	}
+	if len(FilterCategory(entries, "")) != len(entries) {
+		t.Error("expected the empty key to keep every entry")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	h := NewHistory()
+	home := library.Ref{Namespace: "ri", Name: "home line"}
+	arrow := library.Ref{Namespace: "ri", Name: "arrow left line"}
+
+	if !h.ToggleFavorite(home) || !h.IsFavorite(home) {
+		t.Error("expected the icon to be starred")
+	}
+	h.Use(arrow, home, arrow)
+	if h.Usage[arrow.String()] != 2 || len(h.Recent) != 2 || h.Recent[0] != arrow.String() {
+		t.Errorf("expected the last used icon first: %v %v", h.Recent, h.Usage)
+	}
+
+	pinned, favorites, recent := h.Pin(GroupVariants(testEntries))
+	if favorites != 1 || recent != 1 || pinned[0].Ref.Name != "home" || pinned[1].Ref.Name != "arrow left" {
+		t.Errorf("expected the starred icon and then the recent one at the top: %d %d %v", favorites, recent, pinned[:2])
+	}
+	if len(pinned) != len(GroupVariants(testEntries)) {
+		t.Error("expected pinning to keep every group")
+	}
+
+	if h.ToggleFavorite(home) || h.IsFavorite(home) {
+		t.Error("expected the icon to be unstarred")
+	}
+}
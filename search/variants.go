@@ -0,0 +1,48 @@
+package search
+
+import "icon-cli/library"
+
+// the styles of one icon, like arrow left line and arrow left fill
+type Group struct {
+	// the reference without the style
+	Ref library.Ref
+	// in the order the entries came in
+	Variants []Entry
+}
+
+func (g Group) Styles() []string {
+	styles := make([]string, len(g.Variants))
+	for i, v := range g.Variants {
+		styles[i] = v.Record.Style
+		if styles[i] == "" {
+			styles[i] = library.StyleFromName(v.Ref.Name)
+		}
+	}
+	return styles
+}
+
+// groups the entries that only differ in their style, a group sits where
+// its first entry was so ranked entries keep their order
+func GroupVariants(entries []Entry) []Group {
+	var groups []Group
+	at := map[library.Ref]int{}
+	for _, e := range entries {
+		base := library.Ref{Namespace: e.Ref.Namespace, Name: library.BaseName(e.Ref.Name)}
+		if i, has := at[base]; has {
+			groups[i].Variants = append(groups[i].Variants, e)
+			continue
+		}
+		at[base] = len(groups)
+		groups = append(groups, Group{Ref: base, Variants: []Entry{e}})
+	}
+	return groups
+}
+
+// the entries of ranked results, for grouping them
+func ResultEntries(results []Result) []Entry {
+	entries := make([]Entry, len(results))
+	for i, r := range results {
+		entries[i] = r.Entry
+	}
+	return entries
+}
@@ -0,0 +1,57 @@
+package svelte
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+//go:embed Icon.svelte
+var iconTemplate string
+
+//go:embed icon-context.ts
+var IconContext string
+
+func findSVG(node *html.Node) *html.Node {
+	if node.Type == html.ElementNode && node.Data == "svg" {
+		return node
+	}
+	for c := node.FirstChild; c != nil; c = c.NextSibling {
+		found := findSVG(c)
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// wraps the contents of an svg document in the Icon component template
+func Component(svg []byte) (string, error) {
+	doc, err := html.Parse(bytes.NewBuffer(svg))
+	if err != nil {
+		return "", err
+	}
+	root := findSVG(doc)
+	if root == nil {
+		return "", errors.New("no svg element found")
+	}
+
+	buffer := bytes.NewBuffer(nil)
+	for c := root.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.TextNode && strings.TrimSpace(c.Data) == "" {
+			continue
+		}
+		err = html.Render(buffer, c)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return strings.Replace(
+		iconTemplate, "[SVG_CONTENT]",
+		buffer.String(), 1,
+	), nil
+}
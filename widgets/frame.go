@@ -0,0 +1,42 @@
+package widgets
+
+import (
+	"sync"
+
+	"github.com/mum4k/termdash/terminal/terminalapi"
+)
+
+// a terminal that runs graphics protocol output right after termdash
+// flushed a frame, nothing else writes to the terminal then so the output
+// can't interleave with tcell's or be drawn over by it
+type FrameTerminal struct {
+	terminalapi.Terminal
+	lock    sync.Mutex
+	pending []func()
+}
+
+func NewFrameTerminal(terminal terminalapi.Terminal) *FrameTerminal {
+	return &FrameTerminal{
+		Terminal: terminal,
+		lock:     sync.Mutex{},
+	}
+}
+
+func (t *FrameTerminal) Flush() error {
+	err := t.Terminal.Flush()
+	t.lock.Lock()
+	pending := t.pending
+	t.pending = nil
+	t.lock.Unlock()
+	for _, write := range pending {
+		write()
+	}
+	return err
+}
+
+// runs write once the next frame was flushed
+func (t *FrameTerminal) AfterFlush(write func()) {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	t.pending = append(t.pending, write)
+}
@@ -0,0 +1,286 @@
+package widgets
+
+import (
+	"icon-cli/common"
+	"image"
+	"image/color"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+	"github.com/nfnt/resize"
+)
+
+// the bits of each dot in a braille character, indexed by [y][x]
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// draws an image as braille characters, each covering 2x4 pixels, a dot
+// is raised where a pixel is more than half opaque
+func Braille(img image.Image, size image.Point) []string {
+	scaled := resize.Resize(uint(size.X*2), uint(size.Y*4), img, resize.Bilinear)
+	bounds := scaled.Bounds()
+
+	lines := make([]string, size.Y)
+	for y := 0; y < size.Y; y++ {
+		line := strings.Builder{}
+		for x := 0; x < size.X; x++ {
+			r := rune(0x2800)
+			for dy, bits := range brailleBits {
+				for dx, bit := range bits {
+					pixel := color.NRGBAModel.Convert(scaled.At(
+						bounds.Min.X+x*2+dx, bounds.Min.Y+y*4+dy,
+					)).(color.NRGBA)
+					if pixel.A >= 128 {
+						r |= bit
+					}
+				}
+			}
+			line.WriteRune(r)
+		}
+		lines[y] = line.String()
+	}
+	return lines
+}
+
+type GridProps struct {
+	Hovered int
+	// the name shown under each tile
+	Tiles []string
+	// drawn instead of the tiles when there are none
+	Empty string
+	// the size of a tile in cells, including the row for its name
+	TileSize      image.Point
+	KeyboardScope widgetapi.KeyScope
+	MouseScope    widgetapi.MouseScope
+}
+
+// tiles of icon thumbnails, laid out in as many columns as fit
+type Grid struct {
+	props GridProps
+	lock  sync.Mutex
+
+	// the first visible row of tiles
+	scrollTop int
+	columns   int
+	rows      int
+	// rendered thumbnails keyed by tile
+	thumbnails map[int][]string
+
+	// renders the thumbnail of a tile at a square size in pixels
+	Thumbnail func(i int, size int) image.Image
+	OnSelect  func(int)
+	OnHover   func(int)
+}
+
+func NewGrid(tiles ...string) *Grid {
+	return &Grid{
+		props: GridProps{
+			Tiles:         tiles,
+			TileSize:      image.Pt(12, 6),
+			KeyboardScope: widgetapi.KeyScopeFocused,
+			MouseScope:    widgetapi.MouseScopeWidget,
+		},
+		columns:    1,
+		thumbnails: map[int][]string{},
+		lock:       sync.Mutex{},
+	}
+}
+
+func (g *Grid) Props() GridProps {
+	return g.props
+}
+
+func (g *Grid) SetProps(transform func(GridProps) GridProps) {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+	g.props = transform(g.props)
+	g.thumbnails = map[int][]string{}
+	g.scrollTop = 0
+	g.props.Hovered = 0
+	if g.OnHover != nil && len(g.props.Tiles) > 0 {
+		g.OnHover(0)
+	}
+}
+
+// hovers a tile without going through SetProps, like when the grid
+// takes over from a list
+func (g *Grid) Hover(i int) {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+	g.hover(i)
+}
+
+func (g *Grid) hover(i int) {
+	if len(g.props.Tiles) == 0 {
+		return
+	}
+	g.props.Hovered = common.Clamp(i, 0, len(g.props.Tiles)-1)
+	if g.OnHover != nil {
+		g.OnHover(g.props.Hovered)
+	}
+
+	row := g.props.Hovered / g.columns
+	if row < g.scrollTop {
+		g.scrollTop = row
+	}
+	if g.rows > 0 && row >= g.scrollTop+g.rows {
+		g.scrollTop = row - g.rows + 1
+	}
+}
+
+func (g *Grid) thumbnail(i int) []string {
+	if lines, has := g.thumbnails[i]; has {
+		return lines
+	}
+	art := image.Pt(g.props.TileSize.X-2, g.props.TileSize.Y-1)
+	// braille dots are about square, so a square icon is as many pixels
+	// wide as the art is tall
+	size := common.Clamp(art.Y*4, 1, art.X*2)
+	var lines []string
+	if g.Thumbnail != nil {
+		if img := g.Thumbnail(i, size); img != nil {
+			lines = Braille(img, image.Pt(size/2, size/4))
+		}
+	}
+	g.thumbnails[i] = lines
+	return lines
+}
+
+func (g *Grid) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+
+	needAr, err := area.FromSize(g.props.TileSize)
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+
+	if len(g.props.Tiles) == 0 {
+		return draw.Text(cvs, g.props.Empty, image.Pt(0, 0), draw.TextOverrunMode(draw.OverrunModeThreeDot))
+	}
+
+	size := cvs.Area().Size()
+	g.columns = common.Clamp(size.X/g.props.TileSize.X, 1, size.X)
+	g.rows = common.Clamp(size.Y/g.props.TileSize.Y, 1, size.Y)
+	// keeps the hovered tile in view after a resize
+	g.scrollTop = common.Clamp(g.scrollTop, g.props.Hovered/g.columns-g.rows+1, g.props.Hovered/g.columns)
+
+	start := g.scrollTop * g.columns
+	end := common.Clamp(start+g.rows*g.columns, 0, len(g.props.Tiles))
+	for id := start; id < end; id++ {
+		origin := image.Pt(
+			(id%g.columns)*g.props.TileSize.X,
+			(id/g.columns-g.scrollTop)*g.props.TileSize.Y,
+		)
+
+		lines := g.thumbnail(id)
+		for y, line := range lines {
+			offset := (g.props.TileSize.X - len([]rune(line))) / 2
+			err := draw.Text(cvs, line, origin.Add(image.Pt(offset, y)))
+			if err != nil {
+				return err
+			}
+		}
+
+		opt := []draw.TextOption{
+			draw.TextMaxX(origin.X + g.props.TileSize.X - 1),
+			draw.TextOverrunMode(draw.OverrunModeThreeDot),
+		}
+		if id == g.props.Hovered {
+			opt = append(
+				opt, draw.TextCellOpts(
+					cell.FgColor(cell.ColorBlack),
+					cell.BgColor(cell.ColorWhite),
+				),
+			)
+		}
+		name := g.props.Tiles[id]
+		offset := common.Clamp((g.props.TileSize.X-1-len([]rune(name)))/2, 0, g.props.TileSize.X)
+		err := draw.Text(cvs, name, origin.Add(image.Pt(offset, g.props.TileSize.Y-1)), opt...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (g *Grid) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+
+	// plain keys would also be typed into other widgets when the grid
+	// listens globally, so it only takes the arrows then
+	plain := g.props.KeyboardScope != widgetapi.KeyScopeGlobal
+	switch {
+	case k.Key == keyboard.KeyArrowLeft || (plain && k.Key == 'h'):
+		g.hover(g.props.Hovered - 1)
+	case k.Key == keyboard.KeyArrowRight || (plain && k.Key == 'l'):
+		g.hover(g.props.Hovered + 1)
+	case k.Key == keyboard.KeyArrowDown || (plain && k.Key == 'j'):
+		g.hover(g.props.Hovered + g.columns)
+	case k.Key == keyboard.KeyArrowUp || (plain && k.Key == 'k'):
+		g.hover(g.props.Hovered - g.columns)
+	}
+
+	switch k.Key {
+	case keyboard.KeyPgDn:
+		g.hover(g.props.Hovered + g.columns*g.rows)
+	case keyboard.KeyPgUp:
+		g.hover(g.props.Hovered - g.columns*g.rows)
+	case keyboard.KeyEnter:
+		if g.OnSelect != nil && len(g.props.Tiles) > 0 {
+			g.OnSelect(g.props.Hovered)
+		}
+	}
+	return nil
+}
+
+func (g *Grid) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	defer g.lock.Unlock()
+	g.lock.Lock()
+
+	switch m.Button {
+	case mouse.ButtonWheelUp:
+		g.hover(g.props.Hovered - g.columns)
+	case mouse.ButtonWheelDown:
+		g.hover(g.props.Hovered + g.columns)
+	case mouse.ButtonLeft:
+		if m.Position.X < 0 || m.Position.Y < 0 {
+			return nil
+		}
+		column := m.Position.X / g.props.TileSize.X
+		row := m.Position.Y/g.props.TileSize.Y + g.scrollTop
+		id := row*g.columns + column
+		if column >= g.columns || id >= len(g.props.Tiles) {
+			return nil
+		}
+		g.hover(id)
+		if g.OnSelect != nil {
+			g.OnSelect(id)
+		}
+	}
+	return nil
+}
+
+func (g *Grid) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  g.props.TileSize,
+		WantKeyboard: g.props.KeyboardScope,
+		WantMouse:    g.props.MouseScope,
+	}
+}
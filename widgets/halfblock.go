@@ -0,0 +1,83 @@
+package widgets
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/nfnt/resize"
+)
+
+const (
+	UPPER_HALF_BLOCK = '▀'
+	LOWER_HALF_BLOCK = '▄'
+)
+
+// a cell showing two pixels, the top one in the upper half block and the
+// bottom one in the background, nil colors are the terminal's own
+type HalfBlock struct {
+	Rune rune
+	Fg   color.Color
+	Bg   color.Color
+}
+
+func (b HalfBlock) Options() []cell.Option {
+	var opts []cell.Option
+	if b.Fg != nil {
+		opts = append(opts, cell.FgColor(cube(b.Fg)))
+	}
+	if b.Bg != nil {
+		opts = append(opts, cell.BgColor(cube(b.Bg)))
+	}
+	return opts
+}
+
+// the nearest color of the 6x6x6 cube of 256 color terminals, termdash
+// draws nothing finer
+func cube(c color.Color) cell.Color {
+	pixel := color.NRGBAModel.Convert(c).(color.NRGBA)
+	level := func(v uint8) int {
+		return (int(v) + 25) / 51
+	}
+	return cell.ColorRGB6(level(pixel.R), level(pixel.G), level(pixel.B))
+}
+
+// draws an image as cells of two pixels each, laid over the background
+// when there is one, mostly transparent pixels are left out otherwise
+func HalfBlocks(img image.Image, size image.Point, background color.Color) [][]HalfBlock {
+	scaled := resize.Resize(uint(size.X), uint(size.Y*2), img, resize.Bilinear)
+	if background != nil {
+		flat := image.NewNRGBA(scaled.Bounds())
+		draw.Draw(flat, flat.Bounds(), image.NewUniform(background), image.Point{}, draw.Src)
+		draw.Draw(flat, flat.Bounds(), scaled, scaled.Bounds().Min, draw.Over)
+		scaled = flat
+	}
+	bounds := scaled.Bounds()
+
+	pixel := func(x, y int) color.Color {
+		c := color.NRGBAModel.Convert(scaled.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+		if c.A < 128 {
+			return nil
+		}
+		c.A = 255
+		return c
+	}
+
+	rows := make([][]HalfBlock, size.Y)
+	for y := range rows {
+		rows[y] = make([]HalfBlock, size.X)
+		for x := range rows[y] {
+			top, bottom := pixel(x, y*2), pixel(x, y*2+1)
+			switch {
+			case top == nil && bottom == nil:
+				rows[y][x] = HalfBlock{Rune: ' '}
+			case top == nil:
+				rows[y][x] = HalfBlock{Rune: LOWER_HALF_BLOCK, Fg: bottom}
+			default:
+				rows[y][x] = HalfBlock{Rune: UPPER_HALF_BLOCK, Fg: top, Bg: bottom}
+			}
+		}
+	}
+	return rows
+}
@@ -2,12 +2,17 @@ package widgets
 
 import (
 	"bytes"
+	"icon-cli/graphics"
 	"image"
+	"image/color"
+	"io"
+	"log"
 	"strings"
 	"sync"
 	"unicode/utf8"
 
 	"github.com/glibsm/dots"
+	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/private/area"
 	"github.com/mum4k/termdash/private/canvas"
 	"github.com/mum4k/termdash/private/draw"
@@ -18,10 +23,32 @@ import (
 
 type ImageProps struct {
 	Image image.Image
+	// the image was already widened with Stretch, like previews that are
+	// cached that way, braille leaves it as is then
+	Stretched bool
 	//express as width divided by height
 	CharAspectRatio float64
 	AlignX          AlignAt
 	AlignY          AlignAt
+
+	// how the image is drawn, braille, half blocks or a terminal
+	// graphics protocol that draws true pixels
+	Protocol graphics.Protocol
+	// laid under the image by the modes that have color, nil to leave
+	// the terminal's own background
+	Background color.Color
+	// what braille dots are drawn in, which only keep the shape of the
+	// image, nil for the terminal's own foreground
+	Foreground color.Color
+	// where protocol output is written, usually the tty
+	Terminal io.Writer
+	// holds protocol output back until termdash flushed the frame, see
+	// FrameTerminal, it is written right away without one
+	AfterFlush func(func())
+	// where the widget is on the screen, protocols draw at absolute cells
+	Origin image.Point
+	// the size of a cell in pixels, sixel images are scaled to it
+	CellSize image.Point
 }
 
 type Image struct {
@@ -32,6 +59,15 @@ type Image struct {
 	lastDimensions image.Rectangle
 	rendered       string
 	renderOffset   image.Point
+	// the image in half blocks, when it is drawn with them
+	blocks [][]HalfBlock
+
+	// the image and cells last drawn with a protocol
+	placedImage image.Image
+	placedCells image.Rectangle
+	// bumped on every placement so output held back for a frame that a
+	// newer one replaced is dropped
+	generation int
 }
 
 func NewImage() *Image {
@@ -42,6 +78,8 @@ func NewImage() *Image {
 			AlignX:          ALIGN_CENTER,
 			AlignY:          ALIGN_CENTER,
 			Image:           image.NewRGBA(image.Rectangle{}),
+			Protocol:        graphics.PROTOCOL_BRAILLE,
+			CellSize:        graphics.DEFAULT_CELL_SIZE,
 		},
 	}
 }
@@ -57,18 +95,27 @@ func (img *Image) SetProps(transform func(ImageProps) ImageProps) {
 	img.lock.Lock()
 	img.props = transform(img.props)
 
-	if img.props.Image == nil {
+	if img.props.Image == nil || img.usesProtocol() {
 		return
 	}
-	imageSize := img.props.Image.Bounds().Size()
-	width := float64(imageSize.X) / img.props.CharAspectRatio
-	img.props.Image = resize.Resize(
-		uint(width), uint(imageSize.Y),
-		img.props.Image, resize.Bicubic,
-	)
+	if img.props.Protocol == graphics.PROTOCOL_HALFBLOCK {
+		img.renderHalfBlocks()
+		return
+	}
+	if !img.props.Stretched {
+		img.props.Image = Stretch(img.props.Image, img.props.CharAspectRatio)
+	}
 	img.renderImage()
 }
 
+// widens an image for braille, whose characters are taller than they are
+// wide, the ratio is the width of a character divided by its height
+func Stretch(src image.Image, charAspectRatio float64) image.Image {
+	size := src.Bounds().Size()
+	width := float64(size.X) / charAspectRatio
+	return resize.Resize(uint(width), uint(size.Y), src, resize.Bicubic)
+}
+
 func (img *Image) renderImage() {
 	buffer := bytes.NewBuffer(nil)
 	dots.Write(
@@ -94,6 +141,27 @@ func (img *Image) renderImage() {
 	).Min
 }
 
+// half blocks are about square pixels, two to a cell
+func (img *Image) renderHalfBlocks() {
+	if img.canvas == nil || img.props.Image == nil {
+		img.blocks = nil
+		return
+	}
+	space := img.canvas.Area()
+	space.Max.Y *= 2
+	fitted := FitRectangle(space, img.props.Image.Bounds(), FIT_CONTAIN)
+	cells := image.Rect(0, 0, fitted.Dx(), fitted.Dy()/2)
+	if cells.Empty() {
+		img.blocks = nil
+		return
+	}
+	img.blocks = HalfBlocks(img.props.Image, cells.Size(), img.props.Background)
+	img.renderOffset = AlignRectangle(
+		img.canvas.Area(), cells,
+		img.props.AlignX, img.props.AlignY,
+	).Min
+}
+
 func (img *Image) Bounds() image.Point {
 	defer img.lock.Unlock()
 	img.lock.Lock()
@@ -118,6 +186,76 @@ func (img *Image) bounds() image.Point {
 	).Size()
 }
 
+func (img *Image) usesProtocol() bool {
+	switch img.props.Protocol {
+	case graphics.PROTOCOL_KITTY, graphics.PROTOCOL_ITERM, graphics.PROTOCOL_SIXEL:
+		return img.props.Terminal != nil
+	}
+	return false
+}
+
+// the cells an image covers when it is drawn with a protocol
+func (img *Image) protocolCells(cvs *canvas.Canvas) image.Rectangle {
+	cell := img.props.CellSize
+	size := image.Pt(1, 1)
+	if img.props.Image != nil && !img.props.Image.Bounds().Empty() {
+		size = img.props.Image.Bounds().Size()
+	}
+	// the image in cells, which are taller than they are wide, so a square
+	// takes more columns than rows
+	fitted := FitRectangle(cvs.Area(), image.Rect(0, 0, size.X*cell.Y, size.Y*cell.X), FIT_CONTAIN)
+	return AlignRectangle(cvs.Area(), fitted, img.props.AlignX, img.props.AlignY)
+}
+
+// protocol output is written straight to the terminal, after termdash has
+// flushed the frame so it doesn't draw blank cells over it, called with
+// the lock held
+func (img *Image) placeImage(cells image.Rectangle) {
+	img.generation++
+	generation := img.generation
+	props := img.props
+
+	scaled := props.Image
+	if props.Protocol == graphics.PROTOCOL_SIXEL {
+		scaled = resize.Resize(
+			uint(cells.Dx()*props.CellSize.X), uint(cells.Dy()*props.CellSize.Y),
+			props.Image, resize.Bilinear,
+		)
+	}
+	sequence, err := graphics.Encode(props.Protocol, scaled, cells.Size())
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	place := func() {
+		err := graphics.Place(props.Terminal, props.Origin.Add(cells.Min), sequence)
+		if err != nil {
+			log.Println(err)
+		}
+	}
+
+	if props.AfterFlush == nil {
+		place()
+		return
+	}
+	props.AfterFlush(func() {
+		img.lock.Lock()
+		stale := generation != img.generation
+		img.lock.Unlock()
+		if !stale {
+			place()
+		}
+	})
+}
+
+// draws the protocol image again on the next frame, the terminal erases
+// it whenever termdash clears the screen, like after a layout update
+func (img *Image) Invalidate() {
+	defer img.lock.Unlock()
+	img.lock.Lock()
+	img.placedImage = nil
+}
+
 func (img *Image) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 	img.lock.Lock()
 	defer img.lock.Unlock()
@@ -131,10 +269,38 @@ func (img *Image) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		return draw.ResizeNeeded(cvs)
 	}
 
+	// the cells stay blank for the protocol to draw over
+	if img.usesProtocol() {
+		cells := img.protocolCells(cvs)
+		if img.props.Image != nil && (img.props.Image != img.placedImage || !cells.Eq(img.placedCells)) {
+			img.placeImage(cells)
+		}
+		img.placedImage = img.props.Image
+		img.placedCells = cells
+		return nil
+	}
+
+	if img.props.Protocol == graphics.PROTOCOL_HALFBLOCK {
+		if !cvs.Area().Eq(img.lastDimensions) {
+			img.renderHalfBlocks()
+		}
+		for y, row := range img.blocks {
+			for x, block := range row {
+				cvs.SetCell(image.Pt(x, y).Add(img.renderOffset), block.Rune, block.Options()...)
+			}
+		}
+		img.lastDimensions = cvs.Area()
+		return nil
+	}
+
 	if !cvs.Area().Eq(img.lastDimensions) && img.props.Image != nil {
 		img.renderImage()
 	}
 
+	var opts []cell.Option
+	if img.props.Foreground != nil {
+		opts = append(opts, cell.FgColor(cube(img.props.Foreground)))
+	}
 	y := 0
 	x := 0
 	for _, r := range img.rendered {
@@ -144,7 +310,7 @@ func (img *Image) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 			continue
 		}
 
-		cvs.SetCell(image.Pt(x, y).Add(img.renderOffset), r)
+		cvs.SetCell(image.Pt(x, y).Add(img.renderOffset), r, opts...)
 		x++
 	}
 	img.lastDimensions = cvs.Area()
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"icon-cli/common"
 	"image"
-	"log"
+	"sort"
 	"sync"
+	"unicode/utf8"
 
 	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/keyboard"
@@ -28,6 +29,11 @@ func DotPrefix(_ int) string {
 type ListProps struct {
 	Hovered int
 	Rows    []string
+	// drawn instead of the rows when there are none
+	Empty string
+	// the rows picked for a batch action, keyed by their text so the
+	// selection survives the rows changing
+	Selected map[string]bool
 	// a number from 0-1, defines where to start scrolling the viewport
 	ScrollMargin  float64
 	KeyboardScope widgetapi.KeyScope
@@ -38,10 +44,14 @@ type List struct {
 	scroll *ScrollManager
 	props  ListProps
 	lock   sync.Mutex
+	// where a visual range started, -1 when there is none
+	anchor int
 
 	OnSelect func(int)
 	OnHover  func(int)
-	Prefix   func(int) string
+	// called with the number of selected rows whenever it changes
+	OnSelectionChange func(int)
+	Prefix            func(int) string
 }
 
 func NewList(rows ...string) *List {
@@ -50,9 +60,11 @@ func NewList(rows ...string) *List {
 		props: ListProps{
 			Rows:          rows,
 			ScrollMargin:  0.5,
+			Selected:      map[string]bool{},
 			KeyboardScope: widgetapi.KeyScopeFocused,
 			MouseScope:    widgetapi.MouseScopeWidget,
 		},
+		anchor: -1,
 		Prefix: NumberPrefix,
 		lock:   sync.Mutex{},
 	}
@@ -70,13 +82,118 @@ func (l *List) SetProps(transform func(ListProps) ListProps) {
 		ss.Rows = len(l.props.Rows)
 		return ss
 	})
+	if l.props.Selected == nil {
+		l.props.Selected = map[string]bool{}
+	}
+	l.anchor = -1
 	l.props.Hovered = 0
-	if l.OnHover != nil {
+	if l.OnHover != nil && len(l.props.Rows) > 0 {
 		l.OnHover(0)
 	}
 }
 
+// the selected rows, sorted
+func (l *List) Selection() []string {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	return l.selection()
+}
+
+func (l *List) selection() []string {
+	var selected []string
+	for row, has := range l.props.Selected {
+		if has {
+			selected = append(selected, row)
+		}
+	}
+	sort.Strings(selected)
+	return selected
+}
+
+func (l *List) ClearSelection() {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	l.props.Selected = map[string]bool{}
+	l.anchor = -1
+	l.selectionChanged()
+}
+
+func (l *List) selectionChanged() {
+	if l.OnSelectionChange != nil {
+		l.OnSelectionChange(len(l.selection()))
+	}
+}
+
+func (l *List) toggle(row string) {
+	if l.props.Selected[row] {
+		delete(l.props.Selected, row)
+	} else {
+		l.props.Selected[row] = true
+	}
+}
+
+// whether a row is in the visual range being picked
+func (l *List) inRange(id int) bool {
+	if l.anchor < 0 {
+		return false
+	}
+	from, to := l.anchor, l.props.Hovered
+	if from > to {
+		from, to = to, from
+	}
+	return id >= from && id <= to
+}
+
+// starts a visual range at the hovered row, or selects the rows
+// in the range when one was started
+func (l *List) visual() {
+	if len(l.props.Rows) == 0 {
+		return
+	}
+	if l.anchor < 0 {
+		l.anchor = l.props.Hovered
+		return
+	}
+	for id, row := range l.props.Rows {
+		if l.inRange(id) {
+			l.props.Selected[row] = true
+		}
+	}
+	l.anchor = -1
+	l.selectionChanged()
+}
+
+// selects every row, or unselects them when they all are already
+func (l *List) selectAll() {
+	all := true
+	for _, row := range l.props.Rows {
+		if !l.props.Selected[row] {
+			all = false
+			break
+		}
+	}
+	for _, row := range l.props.Rows {
+		if all {
+			delete(l.props.Selected, row)
+		} else {
+			l.props.Selected[row] = true
+		}
+	}
+	l.selectionChanged()
+}
+
+// hovers a row without going through SetProps, like when the list
+// takes over from a grid
+func (l *List) Hover(i int) {
+	defer l.lock.Unlock()
+	l.lock.Lock()
+	l.scrollBy(i - l.props.Hovered)
+}
+
 func (l *List) scrollBy(delta int) {
+	if len(l.props.Rows) == 0 {
+		return
+	}
 	l.props.Hovered = common.Clamp(l.props.Hovered+delta, 0, len(l.props.Rows)-1)
 	if l.OnHover != nil {
 		l.OnHover(l.props.Hovered)
@@ -112,25 +229,36 @@ func (l *List) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
 		state.Canvas = cvs
 		return state
 	})
+	if len(l.props.Rows) == 0 {
+		return draw.Text(cvs, l.props.Empty, image.Pt(0, 0), draw.TextOverrunMode(draw.OverrunModeThreeDot))
+	}
+
 	start, end := l.scroll.Visible()
-	log.Println(start, end, l.props.Rows)
 	for i, row := range l.props.Rows[start:end] {
 		id := i + start
 
 		opt := []draw.TextOption{}
-		if id == l.props.Hovered {
+		switch {
+		case id == l.props.Hovered:
 			opt = append(
 				opt, draw.TextCellOpts(
 					cell.FgColor(cell.ColorBlack),
 					cell.BgColor(cell.ColorWhite),
 				),
 			)
+		case l.props.Selected[row] || l.inRange(id):
+			opt = append(
+				opt, draw.TextCellOpts(
+					cell.FgColor(cell.ColorBlack),
+					cell.BgColor(cell.ColorLime),
+				),
+			)
 		}
 
 		var offset int
 		if l.Prefix != nil {
 			prefix := l.Prefix(id)
-			offset = len(prefix)
+			offset = utf8.RuneCountInString(prefix)
 			draw.Text(cvs, prefix, image.Pt(0, i))
 		}
 
@@ -157,10 +285,26 @@ func (l *List) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) erro
 	case keyboard.KeyPgUp:
 		l.scrollBy(-16)
 	case keyboard.KeyEnter:
-		if l.OnSelect != nil {
+		if l.OnSelect != nil && len(l.props.Rows) > 0 {
 			l.OnSelect(l.props.Hovered)
 		}
 	}
+
+	// plain keys would also be typed into other widgets when the list
+	// listens globally, so it only takes the ctrl variants then
+	plain := l.props.KeyboardScope != widgetapi.KeyScopeGlobal
+	switch {
+	case k.Key == keyboard.KeyCtrlSpace || (plain && k.Key == keyboard.KeySpace):
+		if len(l.props.Rows) > 0 {
+			l.toggle(l.props.Rows[l.props.Hovered])
+			l.selectionChanged()
+		}
+	case k.Key == keyboard.KeyCtrlV || (plain && k.Key == 'v'):
+		l.visual()
+	// ctrl+a is home in text inputs
+	case k.Key == keyboard.KeyCtrlL || (plain && k.Key == 'a'):
+		l.selectAll()
+	}
 	return nil
 }
 
@@ -174,7 +318,7 @@ func (l *List) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
 	case mouse.ButtonWheelDown:
 		l.scrollBy(1)
 	case mouse.ButtonLeft:
-		if l.OnSelect != nil {
+		if l.OnSelect != nil && len(l.props.Rows) > 0 {
 			l.OnSelect(l.props.Hovered)
 		}
 	}
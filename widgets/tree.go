@@ -0,0 +1,307 @@
+package widgets
+
+import (
+	"fmt"
+	"icon-cli/common"
+	"image"
+	"strings"
+	"sync"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/area"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/draw"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgetapi"
+)
+
+const (
+	EXPANDED_MARKER  = "▾ "
+	COLLAPSED_MARKER = "▸ "
+)
+
+// a node of a tree, the nodes under it are hidden while it is collapsed
+type TreeNode struct {
+	// what OnSelect is called with
+	Key   string
+	Label string
+	// drawn after the label, like the number of icons under the node
+	Count     int
+	Children  []*TreeNode
+	Collapsed bool
+}
+
+type TreeProps struct {
+	// the index of the hovered node among the visible ones
+	Hovered int
+	Roots   []*TreeNode
+	// the key of the node that was picked last, it stays highlighted
+	Selected string
+	// drawn instead of the nodes when there are none
+	Empty         string
+	KeyboardScope widgetapi.KeyScope
+	MouseScope    widgetapi.MouseScope
+}
+
+type treeRow struct {
+	node   *TreeNode
+	depth  int
+	parent int
+}
+
+type Tree struct {
+	props TreeProps
+	lock  sync.Mutex
+	// the nodes that aren't hidden by a collapsed parent, in order
+	rows      []treeRow
+	scrollTop int
+
+	OnSelect func(key string)
+	OnHover  func(key string)
+}
+
+func NewTree(roots ...*TreeNode) *Tree {
+	t := &Tree{
+		props: TreeProps{
+			Roots:         roots,
+			KeyboardScope: widgetapi.KeyScopeFocused,
+			MouseScope:    widgetapi.MouseScopeWidget,
+		},
+		lock: sync.Mutex{},
+	}
+	t.flatten()
+	return t
+}
+
+func (t *Tree) Props() TreeProps {
+	return t.props
+}
+
+// the hovered node stays hovered when it is still visible afterwards
+func (t *Tree) SetProps(transform func(TreeProps) TreeProps) {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+	key := t.hoveredKey()
+	t.props = transform(t.props)
+	t.flatten()
+	t.props.Hovered = 0
+	for i, row := range t.rows {
+		if row.node.Key == key {
+			t.props.Hovered = i
+		}
+	}
+}
+
+func (t *Tree) hoveredKey() string {
+	if t.props.Hovered >= len(t.rows) {
+		return ""
+	}
+	return t.rows[t.props.Hovered].node.Key
+}
+
+func (t *Tree) flatten() {
+	t.rows = t.rows[:0]
+	var walk func(nodes []*TreeNode, depth, parent int)
+	walk = func(nodes []*TreeNode, depth, parent int) {
+		for _, node := range nodes {
+			t.rows = append(t.rows, treeRow{node: node, depth: depth, parent: parent})
+			if !node.Collapsed {
+				walk(node.Children, depth+1, len(t.rows)-1)
+			}
+		}
+	}
+	walk(t.props.Roots, 0, -1)
+}
+
+func (t *Tree) hover(i int) {
+	if len(t.rows) == 0 {
+		return
+	}
+	t.props.Hovered = common.Clamp(i, 0, len(t.rows)-1)
+	if t.OnHover != nil {
+		t.OnHover(t.hoveredKey())
+	}
+}
+
+// collapses the hovered node, or moves to its parent when it has nothing
+// to collapse
+func (t *Tree) collapse() {
+	if len(t.rows) == 0 {
+		return
+	}
+	row := t.rows[t.props.Hovered]
+	if len(row.node.Children) > 0 && !row.node.Collapsed {
+		row.node.Collapsed = true
+		t.flatten()
+		return
+	}
+	if row.parent >= 0 {
+		t.hover(row.parent)
+	}
+}
+
+func (t *Tree) expand() {
+	if len(t.rows) == 0 {
+		return
+	}
+	node := t.rows[t.props.Hovered].node
+	if node.Collapsed {
+		node.Collapsed = false
+		t.flatten()
+	}
+}
+
+func (t *Tree) selectHovered() {
+	if len(t.rows) == 0 {
+		return
+	}
+	t.props.Selected = t.hoveredKey()
+	if t.OnSelect != nil {
+		t.OnSelect(t.props.Selected)
+	}
+}
+
+func (t *Tree) Draw(cvs *canvas.Canvas, meta *widgetapi.Meta) error {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+
+	needAr, err := area.FromSize(image.Pt(5, 1))
+	if err != nil {
+		return err
+	}
+	if !needAr.In(cvs.Area()) {
+		return draw.ResizeNeeded(cvs)
+	}
+	if len(t.rows) == 0 {
+		return draw.Text(cvs, t.props.Empty, image.Pt(0, 0), draw.TextOverrunMode(draw.OverrunModeThreeDot))
+	}
+
+	// keeps the hovered node in view
+	height := cvs.Area().Dy()
+	if t.props.Hovered < t.scrollTop {
+		t.scrollTop = t.props.Hovered
+	}
+	if t.props.Hovered >= t.scrollTop+height {
+		t.scrollTop = t.props.Hovered - height + 1
+	}
+	if bottom := len(t.rows) - height; t.scrollTop > bottom && bottom >= 0 {
+		t.scrollTop = bottom
+	}
+
+	width := cvs.Area().Dx()
+	for y := 0; y < height && t.scrollTop+y < len(t.rows); y++ {
+		id := t.scrollTop + y
+		row := t.rows[id]
+
+		marker := "  "
+		if len(row.node.Children) > 0 {
+			marker = EXPANDED_MARKER
+			if row.node.Collapsed {
+				marker = COLLAPSED_MARKER
+			}
+		}
+		label := strings.Repeat("  ", row.depth) + marker + row.node.Label
+		count := fmt.Sprintf(" %d ", row.node.Count)
+		if padding := width - len([]rune(label)) - len(count); padding > 0 {
+			label += strings.Repeat(" ", padding)
+		}
+
+		opt := []draw.TextOption{draw.TextOverrunMode(draw.OverrunModeThreeDot)}
+		switch {
+		case id == t.props.Hovered:
+			opt = append(opt, draw.TextCellOpts(
+				cell.FgColor(cell.ColorBlack),
+				cell.BgColor(cell.ColorWhite),
+			))
+		case row.node.Key == t.props.Selected:
+			opt = append(opt, draw.TextCellOpts(
+				cell.FgColor(cell.ColorBlack),
+				cell.BgColor(cell.ColorLime),
+			))
+		}
+		err := draw.Text(cvs, label+count, image.Pt(0, y), opt...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Tree) Keyboard(k *terminalapi.Keyboard, meta *widgetapi.EventMeta) error {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+
+	switch k.Key {
+	case keyboard.KeyArrowDown:
+		t.hover(t.props.Hovered + 1)
+	case keyboard.KeyArrowUp:
+		t.hover(t.props.Hovered - 1)
+	case keyboard.KeyPgDn:
+		t.hover(t.props.Hovered + 16)
+	case keyboard.KeyPgUp:
+		t.hover(t.props.Hovered - 16)
+	case keyboard.KeyArrowLeft:
+		t.collapse()
+	case keyboard.KeyArrowRight:
+		t.expand()
+	case keyboard.KeyEnter:
+		t.selectHovered()
+	}
+
+	// plain keys would also be typed into other widgets when the tree
+	// listens globally
+	if t.props.KeyboardScope == widgetapi.KeyScopeGlobal {
+		return nil
+	}
+	switch k.Key {
+	case 'j':
+		t.hover(t.props.Hovered + 1)
+	case 'k':
+		t.hover(t.props.Hovered - 1)
+	case 'h':
+		t.collapse()
+	case 'l':
+		t.expand()
+	case keyboard.KeySpace:
+		if len(t.rows) > 0 {
+			node := t.rows[t.props.Hovered].node
+			node.Collapsed = !node.Collapsed
+			t.flatten()
+		}
+	}
+	return nil
+}
+
+func (t *Tree) Mouse(m *terminalapi.Mouse, meta *widgetapi.EventMeta) error {
+	defer t.lock.Unlock()
+	t.lock.Lock()
+
+	switch m.Button {
+	case mouse.ButtonWheelUp:
+		t.hover(t.props.Hovered - 1)
+	case mouse.ButtonWheelDown:
+		t.hover(t.props.Hovered + 1)
+	case mouse.ButtonLeft:
+		// clicks outside of the tree come in at -1,-1 when it listens globally
+		if m.Position.X < 0 || m.Position.Y < 0 {
+			return nil
+		}
+		id := t.scrollTop + m.Position.Y
+		if id >= len(t.rows) {
+			return nil
+		}
+		t.hover(id)
+		t.selectHovered()
+	}
+	return nil
+}
+
+func (t *Tree) Options() widgetapi.Options {
+	return widgetapi.Options{
+		MinimumSize:  image.Pt(5, 1),
+		WantKeyboard: t.props.KeyboardScope,
+		WantMouse:    t.props.MouseScope,
+	}
+}
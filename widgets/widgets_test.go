@@ -3,14 +3,22 @@ package widgets
 import (
 	"context"
 	"fmt"
+	"icon-cli/graphics"
 	"image"
+	"image/color"
 	"log"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/mum4k/termdash"
+	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
 	"github.com/mum4k/termdash/linestyle"
+	"github.com/mum4k/termdash/mouse"
+	"github.com/mum4k/termdash/private/canvas"
+	"github.com/mum4k/termdash/private/faketerm"
 	"github.com/mum4k/termdash/terminal/tcell"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/mum4k/termdash/widgetapi"
@@ -111,3 +119,355 @@ func TestFit(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGrid(t *testing.T) {
+	f, err := os.Open("google.png")
+	if err != nil {
+		t.Error(err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	g := NewGrid()
+	tiles := make([]string, 100)
+	for i := range tiles {
+		tiles[i] = fmt.Sprintf("tile %d", i)
+	}
+	g.SetProps(func(gp GridProps) GridProps {
+		gp.Tiles = tiles
+		return gp
+	})
+	g.Thumbnail = func(int, int) image.Image {
+		return img
+	}
+	err = testWidget(g)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestBraille(t *testing.T) {
+	line := image.NewNRGBA(image.Rect(0, 0, 4, 4))
+	for y := 0; y < 4; y++ {
+		line.Set(0, y, color.Black)
+	}
+	lines := Braille(line, image.Pt(2, 1))
+	if len(lines) != 1 || lines[0] != "⡇⠀" {
+		t.Errorf("expected the left column of dots to be raised, got %q", lines)
+	}
+}
+
+func TestListSelection(t *testing.T) {
+	l := NewList("a", "b", "c")
+	count := 0
+	l.OnSelectionChange = func(c int) {
+		count = c
+	}
+	press := func(key keyboard.Key) {
+		l.Keyboard(&terminalapi.Keyboard{Key: key}, nil)
+	}
+
+	press(keyboard.KeySpace)
+	if selected := l.Selection(); len(selected) != 1 || selected[0] != "a" || count != 1 {
+		t.Errorf("expected the hovered row to be selected, got %v", selected)
+	}
+
+	l.SetProps(func(lp ListProps) ListProps {
+		lp.Rows = []string{"c", "d"}
+		return lp
+	})
+	press('a')
+	if selected := l.Selection(); len(selected) != 3 || count != 3 {
+		t.Errorf("expected the selection to survive new rows, got %v", selected)
+	}
+	press('a')
+	if selected := l.Selection(); len(selected) != 1 || count != 1 {
+		t.Errorf("expected select all to toggle, got %v", selected)
+	}
+
+	// plain keys belong to other widgets when the list listens globally
+	l.SetProps(func(lp ListProps) ListProps {
+		lp.KeyboardScope = widgetapi.KeyScopeGlobal
+		return lp
+	})
+	press(keyboard.KeySpace)
+	if count != 1 {
+		t.Error("expected space to be ignored by a global list")
+	}
+	press(keyboard.KeyCtrlSpace)
+	if count != 2 {
+		t.Error("expected ctrl+space to select")
+	}
+	// ctrl+a moves the cursor of the search
+	press(keyboard.KeyCtrlA)
+	if count != 2 {
+		t.Error("expected ctrl+a to be left to the search")
+	}
+	press(keyboard.KeyCtrlL)
+	if count != 3 {
+		t.Errorf("expected ctrl+l to select all, got %d", count)
+	}
+}
+
+func TestHalfBlocks(t *testing.T) {
+	red := color.NRGBA{R: 255, A: 255}
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(0, 0, red)
+	img.Set(1, 1, red)
+
+	blocks := HalfBlocks(img, image.Pt(2, 1), nil)
+	if top := blocks[0][0]; top.Rune != UPPER_HALF_BLOCK || top.Fg != color.Color(red) || top.Bg != nil {
+		t.Errorf("expected an upper half block on the terminal background, got %+v", top)
+	}
+	if bottom := blocks[0][1]; bottom.Rune != LOWER_HALF_BLOCK || bottom.Fg != color.Color(red) {
+		t.Errorf("expected a lower half block, got %+v", bottom)
+	}
+
+	white := color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	blocks = HalfBlocks(img, image.Pt(2, 1), white)
+	if top := blocks[0][0]; top.Rune != UPPER_HALF_BLOCK || top.Bg != color.Color(white) {
+		t.Errorf("expected the background under the top pixel, got %+v", top)
+	}
+
+	// the colors are the nearest ones of the 256 color palette
+	opts := cell.NewOptions(HalfBlock{
+		Rune: UPPER_HALF_BLOCK,
+		Fg:   color.NRGBA{R: 0xf0, G: 0x1a, A: 0xff},
+		Bg:   color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 0xff},
+	}.Options()...)
+	if opts.FgColor != cell.ColorRGB6(5, 1, 0) || opts.BgColor != cell.ColorRGB6(3, 3, 3) {
+		t.Errorf("expected the colors rounded to the color cube, got %v %v", opts.FgColor, opts.BgColor)
+	}
+}
+
+func TestTreeNavigation(t *testing.T) {
+	tree := NewTree(
+		&TreeNode{Key: "ri", Label: "ri", Count: 3, Children: []*TreeNode{
+			{Key: "ri/Arrows", Label: "Arrows", Count: 2},
+			{Key: "ri/System", Label: "System", Count: 1},
+		}},
+		&TreeNode{Key: "mdi", Label: "mdi", Count: 1},
+	)
+	picked := ""
+	tree.OnSelect = func(key string) {
+		picked = key
+	}
+	press := func(key keyboard.Key) {
+		tree.Keyboard(&terminalapi.Keyboard{Key: key}, nil)
+	}
+
+	press(keyboard.KeyArrowDown)
+	press(keyboard.KeyArrowDown)
+	press(keyboard.KeyEnter)
+	if picked != "ri/System" {
+		t.Errorf("expected the second category to be picked, got %q", picked)
+	}
+
+	// collapsing a leaf moves to its parent, then the parent collapses
+	press(keyboard.KeyArrowLeft)
+	press(keyboard.KeyArrowLeft)
+	press(keyboard.KeyArrowDown)
+	press(keyboard.KeyEnter)
+	if picked != "mdi" {
+		t.Errorf("expected the categories to be hidden, got %q", picked)
+	}
+
+	// clicks outside of the tree and below its nodes pick nothing
+	picked = ""
+	tree.Mouse(&terminalapi.Mouse{Position: image.Pt(-1, -1), Button: mouse.ButtonLeft}, nil)
+	tree.Mouse(&terminalapi.Mouse{Position: image.Pt(2, 5), Button: mouse.ButtonLeft}, nil)
+	if picked != "" {
+		t.Errorf("expected clicks outside of the nodes to be ignored, got %q", picked)
+	}
+	tree.Mouse(&terminalapi.Mouse{Position: image.Pt(2, 1), Button: mouse.ButtonLeft}, nil)
+	if picked != "mdi" {
+		t.Errorf("expected a click to pick the node under it, got %q", picked)
+	}
+
+	tree.SetProps(func(tp TreeProps) TreeProps {
+		tp.Roots = tp.Roots[1:]
+		return tp
+	})
+	if tree.Props().Hovered != 0 {
+		t.Errorf("expected the hovered node to stay hovered, got %d", tree.Props().Hovered)
+	}
+}
+
+// counts the protocol output written
+type syncBuffer struct {
+	lock   sync.Mutex
+	writes int
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	defer b.lock.Unlock()
+	b.lock.Lock()
+	b.writes++
+	return len(p), nil
+}
+
+func (b *syncBuffer) Writes() int {
+	defer b.lock.Unlock()
+	b.lock.Lock()
+	return b.writes
+}
+
+func TestImageInvalidate(t *testing.T) {
+	terminal := &syncBuffer{}
+	img := NewImage()
+	img.SetProps(func(ip ImageProps) ImageProps {
+		ip.Image = image.NewNRGBA(image.Rect(0, 0, 4, 4))
+		ip.Protocol = graphics.PROTOCOL_KITTY
+		ip.Terminal = terminal
+		return ip
+	})
+	cvs, err := canvas.New(image.Rect(0, 0, 20, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	draw := func() int {
+		before := terminal.Writes()
+		err := img.Draw(cvs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return terminal.Writes() - before
+	}
+
+	if draw() == 0 {
+		t.Fatal("expected the image to be placed")
+	}
+	if draw() != 0 {
+		t.Error("expected an unchanged image to be left alone")
+	}
+	img.Invalidate()
+	if draw() == 0 {
+		t.Error("expected the image to be placed again after the screen was cleared")
+	}
+}
+
+func TestFrameTerminal(t *testing.T) {
+	fake, err := faketerm.New(image.Pt(20, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	frames := NewFrameTerminal(fake)
+	output := &syncBuffer{}
+	img := NewImage()
+	place := func(size int) {
+		img.SetProps(func(ip ImageProps) ImageProps {
+			ip.Image = image.NewNRGBA(image.Rect(0, 0, size, size))
+			ip.Protocol = graphics.PROTOCOL_KITTY
+			ip.Terminal = output
+			ip.AfterFlush = frames.AfterFlush
+			return ip
+		})
+		cvs, err := canvas.New(image.Rect(0, 0, 20, 10))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = img.Draw(cvs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	place(4)
+	if output.Writes() != 0 {
+		t.Fatal("expected the output to wait for the frame")
+	}
+	// a newer image replaces the one that was waiting
+	place(8)
+	err = frames.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Writes() != 1 {
+		t.Errorf("expected only the newest image to be written, got %d writes", output.Writes())
+	}
+	frames.Flush()
+	if output.Writes() != 1 {
+		t.Error("expected the output to be written once")
+	}
+}
+
+func TestProtocolCells(t *testing.T) {
+	img := NewImage()
+	cvs, err := canvas.New(image.Rect(0, 0, 40, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cells := func(size image.Point) image.Point {
+		img.SetProps(func(ip ImageProps) ImageProps {
+			ip.Image = image.NewNRGBA(image.Rectangle{Max: size})
+			ip.Protocol = graphics.PROTOCOL_KITTY
+			ip.Terminal = &syncBuffer{}
+			ip.CellSize = image.Pt(10, 20)
+			return ip
+		})
+		return img.protocolCells(cvs).Size()
+	}
+
+	if got := cells(image.Pt(200, 200)); got != image.Pt(20, 10) {
+		t.Errorf("expected a square to take twice as many columns as rows, got %v", got)
+	}
+	// two variants next to each other are twice as wide
+	if got := cells(image.Pt(400, 200)); got != image.Pt(40, 10) {
+		t.Errorf("expected a wide image to keep its aspect, got %v", got)
+	}
+}
+
+func TestGridKeys(t *testing.T) {
+	g := NewGrid("a", "b", "c")
+	press := func(key keyboard.Key) {
+		g.Keyboard(&terminalapi.Keyboard{Key: key}, nil)
+	}
+
+	press('l')
+	if g.Props().Hovered != 1 {
+		t.Errorf("expected l to move right, got %d", g.Props().Hovered)
+	}
+
+	// plain keys belong to the search when the grid listens globally
+	g.SetProps(func(gp GridProps) GridProps {
+		gp.KeyboardScope = widgetapi.KeyScopeGlobal
+		return gp
+	})
+	hovered := g.Props().Hovered
+	press('l')
+	press('j')
+	if g.Props().Hovered != hovered {
+		t.Errorf("expected plain keys to be ignored by a global grid, got %d", g.Props().Hovered)
+	}
+	press(keyboard.KeyArrowRight)
+	if g.Props().Hovered != hovered+1 {
+		t.Errorf("expected the arrows to move, got %d", g.Props().Hovered)
+	}
+}
+
+func TestImageStretched(t *testing.T) {
+	img := NewImage()
+	square := image.NewNRGBA(image.Rect(0, 0, 8, 8))
+	img.SetProps(func(ip ImageProps) ImageProps {
+		ip.Image = square
+		return ip
+	})
+	if size := img.Props().Image.Bounds().Size(); size != image.Pt(10, 8) {
+		t.Errorf("expected braille to widen the image, got %v", size)
+	}
+
+	stretched := Stretch(square, img.Props().CharAspectRatio)
+	img.SetProps(func(ip ImageProps) ImageProps {
+		ip.Image = stretched
+		ip.Stretched = true
+		return ip
+	})
+	if img.Props().Image != stretched {
+		t.Error("expected an image that was already stretched to be left alone")
+	}
+}